@@ -0,0 +1,203 @@
+package bodies
+
+import "math"
+
+// Body physical constants.
+//
+// Name:           name of the body,
+// GM:             standard gravitational parameter          (m^3/s^2),
+// Mass:           mass derived from GM and CODATA 2018 G    (kg),
+// Radius:         equatorial radius                         (m),
+// J2:             second zonal harmonic, 0 if unknown       (unitless),
+// RotationPeriod: sidereal rotation period, < 0 retrograde  (s),
+// SOI:            Laplace sphere of influence, 0 for Sun    (m).
+//
+// Mass can be passed directly to functions which accept masses such as
+// gravity.Force, gravity.Period and gravity.OrbitalElements.
+//
+// https://ssd.jpl.nasa.gov/astro_par.html
+type Body struct {
+	Name           string
+	GM             float64
+	Mass           float64
+	Radius         float64
+	J2             float64
+	RotationPeriod float64
+	SOI            float64
+}
+
+// RotationRate (rad/s), negative when retrograde.
+func (b Body) RotationRate() float64 {
+	if b.RotationPeriod == 0 {
+		return 0
+	}
+	return 2 * math.Pi / b.RotationPeriod
+}
+
+var (
+	Sun = Body{
+		Name:           "Sun",
+		GM:             1.32712440041e20,
+		Mass:           1.98841e30,
+		Radius:         6.957e8,
+		J2:             2.2e-7,
+		RotationPeriod: 2192832,
+		SOI:            0,
+	}
+	Mercury = Body{
+		Name:           "Mercury",
+		GM:             2.2031868551e13,
+		Mass:           3.30100e23,
+		Radius:         2.44053e6,
+		J2:             5.03e-5,
+		RotationPeriod: 5067032,
+		SOI:            1.1241e8,
+	}
+	Venus = Body{
+		Name:           "Venus",
+		GM:             3.24858592e14,
+		Mass:           4.86731e24,
+		Radius:         6.0518e6,
+		J2:             4.458e-6,
+		RotationPeriod: -20996755,
+		SOI:            6.1628e8,
+	}
+	Earth = Body{
+		Name:           "Earth",
+		GM:             3.986004418e14,
+		Mass:           5.97217e24,
+		Radius:         6.378137e6,
+		J2:             1.08262668e-3,
+		RotationPeriod: 86164.0905,
+		SOI:            9.2465e8,
+	}
+	Moon = Body{
+		Name:           "Moon",
+		GM:             4.902800066e12,
+		Mass:           7.34579e22,
+		Radius:         1.7381e6,
+		J2:             2.0330e-4,
+		RotationPeriod: 2360591.5,
+		SOI:            6.6183e7,
+	}
+	Mars = Body{
+		Name:           "Mars",
+		GM:             4.282837362e13,
+		Mass:           6.41691e23,
+		Radius:         3.39619e6,
+		J2:             1.96045e-3,
+		RotationPeriod: 88642.663,
+		SOI:            5.7723e8,
+	}
+	Jupiter = Body{
+		Name:           "Jupiter",
+		GM:             1.26686534e17,
+		Mass:           1.89812e27,
+		Radius:         7.1492e7,
+		J2:             1.4736e-2,
+		RotationPeriod: 35730,
+		SOI:            4.8220e10,
+	}
+	Io = Body{
+		Name:           "Io",
+		GM:             5.959916e12,
+		Mass:           8.92965e22,
+		Radius:         1.8216e6,
+		J2:             1.8459e-3,
+		RotationPeriod: 152853.5,
+		SOI:            7.8344e6,
+	}
+	Europa = Body{
+		Name:           "Europa",
+		GM:             3.202739e12,
+		Mass:           4.79861e22,
+		Radius:         1.5608e6,
+		J2:             4.355e-4,
+		RotationPeriod: 306822.0,
+		SOI:            9.7244e6,
+	}
+	Ganymede = Body{
+		Name:           "Ganymede",
+		GM:             9.887834e12,
+		Mass:           1.48148e23,
+		Radius:         2.6312e6,
+		J2:             1.2706e-4,
+		RotationPeriod: 618153.4,
+		SOI:            2.4350e7,
+	}
+	Callisto = Body{
+		Name:           "Callisto",
+		GM:             7.179289e12,
+		Mass:           1.07566e23,
+		Radius:         2.4103e6,
+		J2:             3.27e-5,
+		RotationPeriod: 1441931.2,
+		SOI:            3.7681e7,
+	}
+	Saturn = Body{
+		Name:           "Saturn",
+		GM:             3.7931187e16,
+		Mass:           5.68317e26,
+		Radius:         6.0268e7,
+		J2:             1.6298e-2,
+		RotationPeriod: 38018,
+		SOI:            5.4808e10,
+	}
+	Titan = Body{
+		Name:           "Titan",
+		GM:             8.978138e12,
+		Mass:           1.34518e23,
+		Radius:         2.57473e6,
+		J2:             3.15e-5,
+		RotationPeriod: 1377684.4,
+		SOI:            4.3321e7,
+	}
+	Uranus = Body{
+		Name:           "Uranus",
+		GM:             5.793939e15,
+		Mass:           8.68097e25,
+		Radius:         2.5559e7,
+		J2:             3.34343e-3,
+		RotationPeriod: -62064,
+		SOI:            5.1840e10,
+	}
+	Neptune = Body{
+		Name:           "Neptune",
+		GM:             6.836529e15,
+		Mass:           1.02431e26,
+		Radius:         2.4764e7,
+		J2:             3.411e-3,
+		RotationPeriod: 57996,
+		SOI:            8.6777e10,
+	}
+	Triton = Body{
+		Name:           "Triton",
+		GM:             1.427598e12,
+		Mass:           2.13895e22,
+		Radius:         1.3534e6,
+		J2:             0,
+		RotationPeriod: -507760.2,
+		SOI:            1.1963e7,
+	}
+	Pluto = Body{
+		Name:           "Pluto",
+		GM:             8.6961e11,
+		Mass:           1.30292e22,
+		Radius:         1.1883e6,
+		J2:             0,
+		RotationPeriod: -551856.7,
+		SOI:            3.1469e9,
+	}
+	Charon = Body{
+		Name:           "Charon",
+		GM:             1.0588e11,
+		Mass:           1.58638e21,
+		Radius:         6.06e5,
+		J2:             0,
+		RotationPeriod: -551856.7,
+		SOI:            8.4382e6,
+	}
+)
+
+// Planets of the solar system in order of distance from the Sun.
+var Planets = []Body{Mercury, Venus, Earth, Mars, Jupiter, Saturn, Uranus, Neptune}
@@ -0,0 +1,42 @@
+package bodies_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+func TestBodies(t *testing.T) {
+	t.Run("succeed in calculating the period of the moon around earth", func(t *testing.T) {
+		p := gravity.Period(384399000, bodies.Earth.Mass, bodies.Moon.Mass)
+		require.Equal(t, "27.3", fmt.Sprintf("%.1f", p/86400))
+	})
+	t.Run("succeed in calculating the period of earth around the sun", func(t *testing.T) {
+		p := gravity.Period(149597870700, bodies.Sun.Mass, bodies.Earth.Mass)
+		require.Equal(t, "365.3", fmt.Sprintf("%.1f", p/86400))
+	})
+	t.Run("succeed in calculating surface gravity of earth", func(t *testing.T) {
+		f := gravity.Force(f64.Vec3{}, f64.Vec3{bodies.Earth.Radius, 0, 0}, bodies.Earth.Mass, 1)
+		require.Equal(t, "-9.80", fmt.Sprintf("%.2f", f[0]))
+	})
+	t.Run("succeed in calculating orbital elements of a low earth orbit", func(t *testing.T) {
+		r := f64.Vec3{bodies.Earth.Radius + 400000, 0, 0}
+		v := f64.Vec3{0, 7668.6, 0}
+		a, _, _, _, _, _ := gravity.OrbitalElements(r, v, bodies.Earth.Mass, 0)
+		require.Equal(t, "678", fmt.Sprintf("%.0f", a/10000))
+	})
+	t.Run("succeed in calculating rotation rates", func(t *testing.T) {
+		require.Equal(t, "7.2921e-05", fmt.Sprintf("%.4e", bodies.Earth.RotationRate()))
+		require.Less(t, bodies.Venus.RotationRate(), float64(0))
+	})
+	t.Run("succeed in giving every planet a sphere of influence", func(t *testing.T) {
+		require.Len(t, bodies.Planets, 8)
+		for _, p := range bodies.Planets {
+			require.Greater(t, p.SOI, p.Radius, p.Name)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+// command converting one input record into output records with columns.
+type command struct {
+	columns []string
+	run     func(c env, in fields) ([]record, error)
+}
+
+// env of a command run.
+type env struct {
+	opts options
+	mu   float64
+}
+
+// angle from the units of the command line to radians.
+func (c env) angle(x float64) float64 {
+	if c.opts.deg {
+		return gravity.Radians(x)
+	}
+	return x
+}
+
+// degrees from radians to the units of the command line.
+func (c env) degrees(x float64) float64 {
+	if c.opts.deg {
+		return gravity.Degrees(x)
+	}
+	return x
+}
+
+// elements of an input record.
+func (c env) elements(in *fields) (a, e, w, lan, i, m, mu float64) {
+	a, e = in.float("a"), in.float("e")
+	w, lan, i = c.angle(in.float("w")), c.angle(in.float("lan")), c.angle(in.float("i"))
+	m = c.angle(in.float("m"))
+	mu = in.optional("mu", c.mu)
+	return
+}
+
+// maxStates written by propagate for each input record.
+const maxStates = 1000000
+
+// primaries selectable with -body by lower case name.
+var primaries = map[string]float64{}
+
+func init() {
+	for _, b := range []bodies.Body{
+		bodies.Sun, bodies.Mercury, bodies.Venus, bodies.Earth, bodies.Moon, bodies.Mars,
+		bodies.Jupiter, bodies.Saturn, bodies.Uranus, bodies.Neptune, bodies.Pluto,
+	} {
+		primaries[strings.ToLower(b.Name)] = b.GM
+	}
+}
+
+var commands = map[string]command{
+	"elements": {
+		columns: []string{"a", "e", "w", "lan", "i", "m"},
+		run: func(c env, in fields) ([]record, error) {
+			r := f64.Vec3{in.float("rx"), in.float("ry"), in.float("rz")}
+			v := f64.Vec3{in.float("vx"), in.float("vy"), in.float("vz")}
+			mu := in.optional("mu", c.mu)
+			if in.err != nil {
+				return nil, in.err
+			}
+			a, e, w, lan, i, m := gravity.OrbitalElementsMu(r, v, mu)
+			return []record{{
+				"a": a, "e": e, "w": c.degrees(w), "lan": c.degrees(lan), "i": c.degrees(i), "m": c.degrees(m),
+			}}, nil
+		},
+	},
+	"state": {
+		columns: []string{"rx", "ry", "rz", "vx", "vy", "vz"},
+		run: func(c env, in fields) ([]record, error) {
+			a, e, w, lan, i, m, mu := c.elements(&in)
+			t := in.optional("t", 0)
+			if in.err != nil {
+				return nil, in.err
+			}
+			r, v := gravity.StateVectorsMu(a, e, w, lan, i, m, t, mu)
+			return []record{state(r, v)}, nil
+		},
+	},
+	"period": {
+		columns: []string{"period", "periapsis", "apoapsis"},
+		run: func(c env, in fields) ([]record, error) {
+			a, e := in.float("a"), in.float("e")
+			mu := in.optional("mu", c.mu)
+			if in.err != nil {
+				return nil, in.err
+			}
+			return []record{{
+				"period":    gravity.PeriodMu(a, mu),
+				"periapsis": gravity.Periapsis(a, e),
+				"apoapsis":  gravity.Apoapsis(a, e),
+			}}, nil
+		},
+	},
+	"propagate": {
+		columns: []string{"index", "t", "rx", "ry", "rz", "vx", "vy", "vz"},
+		run: func(c env, in fields) ([]record, error) {
+			a, e, w, lan, i, m, mu := c.elements(&in)
+			if in.err != nil {
+				return nil, in.err
+			}
+			if !(e >= 0 && e < 1) {
+				return nil, fmt.Errorf("record %d: propagate requires 0 <= e < 1, got %g", in.index, e)
+			}
+			span := (c.opts.to - c.opts.from) / c.opts.step
+			if !(c.opts.step > 0) || !(span >= 0) {
+				return nil, fmt.Errorf("propagate requires -step > 0 and -to >= -from")
+			}
+			if span >= maxStates {
+				return nil, fmt.Errorf("propagate would write more than %d states per record, increase -step", maxStates)
+			}
+			n := int(span) + 1
+			r := make([]f64.Vec3, n)
+			v := make([]f64.Vec3, n)
+			gravity.StateVectorsStep(a, e, w, lan, i, m, mu, c.opts.from, c.opts.step, r, v)
+			out := make([]record, n)
+			for k := range out {
+				out[k] = state(r[k], v[k])
+				out[k]["index"] = float64(in.index)
+				out[k]["t"] = c.opts.from + float64(k)*c.opts.step
+			}
+			return out, nil
+		},
+	},
+	"hohmann": {
+		columns: []string{"dv1", "dv2", "dv", "tof"},
+		run: func(c env, in fields) ([]record, error) {
+			r1, r2 := in.float("r1"), in.float("r2")
+			mu := in.optional("mu", c.mu)
+			if in.err != nil {
+				return nil, in.err
+			}
+			dv1, dv2, tof := gravity.Hohmann(r1, r2, mu)
+			return []record{{"dv1": dv1, "dv2": dv2, "dv": dv1 + dv2, "tof": tof}}, nil
+		},
+	},
+}
+
+func state(r, v f64.Vec3) record {
+	return record{"rx": r[0], "ry": r[1], "rz": r[2], "vx": v[0], "vy": v[1], "vz": v[2]}
+}
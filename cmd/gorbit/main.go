@@ -0,0 +1,148 @@
+// Command gorbit exposes the orbit conversions, propagation and transfer
+// planning of the gorbit packages on the command line.
+//
+// Usage:
+//
+//	gorbit [flags] <command> [flags]
+//
+// Commands:
+//
+//	elements   Keplerian elements from state vectors  (rx ry rz vx vy vz → a e w lan i m)
+//	state      state vectors from Keplerian elements  (a e w lan i m [t] → rx ry rz vx vy vz)
+//	period     period and apsides                     (a e → period periapsis apoapsis)
+//	propagate  state vectors over a time range        (a e w lan i m → t rx ry rz vx vy vz)
+//	hohmann    transfer between circular orbits       (r1 r2 → dv1 dv2 dv tof)
+//
+// Records are read from standard input or -in as JSON (an object or an
+// array of objects) or CSV (a header row of field names) which is detected
+// from the first character. Results are written to standard output or -out
+// as a JSON array or CSV depending on -format. Every record may set
+// mu to override the standard gravitational parameter of -body. Units are
+// SI (m, m/s, s, m^3/s^2) and angles are radians unless -deg is set.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// options shared by all commands.
+type options struct {
+	body    string
+	deg     bool
+	from    float64
+	to      float64
+	step    float64
+	format  string
+	inFile  string
+	outFile string
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := options{}
+	fs := flag.NewFlagSet("gorbit", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.body, "body", "earth", "primary body providing the default mu")
+	fs.BoolVar(&opts.deg, "deg", false, "read and write angles in degrees")
+	fs.Float64Var(&opts.from, "from", 0, "propagate: first time since epoch (s)")
+	fs.Float64Var(&opts.to, "to", 0, "propagate: last time since epoch (s)")
+	fs.Float64Var(&opts.step, "step", 60, "propagate: time between states (s)")
+	fs.StringVar(&opts.format, "format", "json", "output format json or csv")
+	fs.StringVar(&opts.inFile, "in", "", "input file (default standard input)")
+	fs.StringVar(&opts.outFile, "out", "", "output file (default standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gorbit [flags] elements|state|period|propagate|hohmann")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+	name := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "gorbit: unknown command %q\n", name)
+		fs.Usage()
+		return 2
+	}
+
+	if err := execute(cmd, opts, stdin, stdout); err != nil {
+		fmt.Fprintf(stderr, "gorbit: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func execute(cmd command, opts options, stdin io.Reader, stdout io.Writer) error {
+	mu, ok := primaries[strings.ToLower(opts.body)]
+	if !ok {
+		names := make([]string, 0, len(primaries))
+		for name := range primaries {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown body %q, expected one of %s", opts.body, strings.Join(names, ", "))
+	}
+
+	if opts.format != "json" && opts.format != "csv" {
+		return fmt.Errorf("unknown format %q", opts.format)
+	}
+
+	in := stdin
+	if opts.inFile != "" {
+		f, err := os.Open(opts.inFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	records, err := readRecords(in)
+	if err != nil {
+		return err
+	}
+
+	e := env{opts: opts, mu: mu}
+	results := []record{}
+	for k, rec := range records {
+		out, err := cmd.run(e, fields{record: rec, index: k})
+		if err != nil {
+			return err
+		}
+		for _, o := range out {
+			if err := o.finite(k, cmd.columns); err != nil {
+				return err
+			}
+		}
+		results = append(results, out...)
+	}
+
+	out := stdout
+	if opts.outFile != "" {
+		f, err := os.Create(opts.outFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return writeRecords(out, opts.format, cmd.columns, results)
+}
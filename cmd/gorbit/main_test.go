@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func gorbit(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestRun(t *testing.T) {
+	t.Run("succeed in converting elements to state and back", func(t *testing.T) {
+		in := `{"a": 7000000, "e": 0.1, "w": 30, "lan": 40, "i": 51.6, "m": 10}`
+		out, _, code := gorbit(t, in, "-deg", "state")
+		require.Equal(t, 0, code)
+
+		out, _, code = gorbit(t, out, "-deg", "elements")
+		require.Equal(t, 0, code)
+		got := []map[string]float64{}
+		require.NoError(t, json.Unmarshal([]byte(out), &got))
+		require.Len(t, got, 1)
+		require.Equal(t, "7000000.0", fmt.Sprintf("%.1f", got[0]["a"]))
+		require.Equal(t, "51.600", fmt.Sprintf("%.3f", got[0]["i"]))
+		require.Equal(t, "10.000", fmt.Sprintf("%.3f", got[0]["m"]))
+	})
+	t.Run("succeed in planning a hohmann transfer from csv", func(t *testing.T) {
+		out, _, code := gorbit(t, "r1,r2\n6678000,42164000\n", "hohmann", "-format", "csv")
+		require.Equal(t, 0, code)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Equal(t, "dv1,dv2,dv,tof", lines[0])
+		require.True(t, strings.HasPrefix(lines[1], "2425.76"))
+	})
+	t.Run("succeed in propagating over a time range", func(t *testing.T) {
+		in := `[{"a": 7000000, "e": 0, "w": 0, "lan": 0, "i": 0, "m": 0}, {"a": 8000000, "e": 0, "w": 0, "lan": 0, "i": 0, "m": 0}]`
+		out, _, code := gorbit(t, in, "-format", "csv", "propagate", "-to", "120", "-step", "60")
+		require.Equal(t, 0, code)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Len(t, lines, 7)
+		require.True(t, strings.HasPrefix(lines[4], "1,0,8000000,"))
+	})
+	t.Run("succeed in using the mu of another body", func(t *testing.T) {
+		earth, _, _ := gorbit(t, `{"a": 7000000, "e": 0}`, "period")
+		mars, _, code := gorbit(t, `{"a": 7000000, "e": 0}`, "-body", "Mars", "period")
+		require.Equal(t, 0, code)
+		require.NotEqual(t, earth, mars)
+	})
+	t.Run("fail on a missing field", func(t *testing.T) {
+		_, stderr, code := gorbit(t, `{"a": 7000000}`, "period")
+		require.Equal(t, 1, code)
+		require.Contains(t, stderr, `record 0: missing field "e"`)
+	})
+	t.Run("fail to propagate a hyperbolic orbit", func(t *testing.T) {
+		in := `{"a": -7000000, "e": 1.5, "w": 0, "lan": 0, "i": 0, "m": 0}`
+		_, stderr, code := gorbit(t, in, "propagate", "-to", "120")
+		require.Equal(t, 1, code)
+		require.Contains(t, stderr, "record 0: propagate requires 0 <= e < 1, got 1.5")
+	})
+	t.Run("fail to propagate too many states", func(t *testing.T) {
+		in := `{"a": 7000000, "e": 0, "w": 0, "lan": 0, "i": 0, "m": 0}`
+		_, stderr, code := gorbit(t, in, "propagate", "-to", "1e300", "-step", "1")
+		require.Equal(t, 1, code)
+		require.Contains(t, stderr, "propagate would write more than 1000000 states per record")
+	})
+	t.Run("fail on a result which is not finite", func(t *testing.T) {
+		_, stderr, code := gorbit(t, `{"a": 7000000, "e": 0.1, "mu": 0}`, "period")
+		require.Equal(t, 1, code)
+		require.Contains(t, stderr, "record 0: period is +Inf")
+	})
+	t.Run("fail on an unknown command", func(t *testing.T) {
+		_, stderr, code := gorbit(t, "", "launch")
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, `unknown command "launch"`)
+	})
+	t.Run("fail on an unknown body", func(t *testing.T) {
+		_, stderr, code := gorbit(t, `{"a": 7000000, "e": 0}`, "-body", "vulcan", "period")
+		require.Equal(t, 1, code)
+		require.Contains(t, stderr, `unknown body "vulcan"`)
+	})
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// record of named values.
+type record map[string]float64
+
+// fields of an input record which records the first missing field.
+type fields struct {
+	record
+	index int
+	err   error
+}
+
+func (f *fields) float(name string) float64 {
+	v, ok := f.record[name]
+	if !ok && f.err == nil {
+		f.err = fmt.Errorf("record %d: missing field %q", f.index, name)
+	}
+	return v
+}
+
+func (f *fields) optional(name string, fallback float64) float64 {
+	if v, ok := f.record[name]; ok {
+		return v
+	}
+	return fallback
+}
+
+// finite checks that every value of the columns can be written, JSON has
+// no NaN or infinity.
+func (r record) finite(index int, columns []string) error {
+	for _, name := range columns {
+		if v := r[name]; math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("record %d: %s is %v", index, name, v)
+		}
+	}
+	return nil
+}
+
+// readRecords in JSON (an object or an array of objects) or CSV (a header
+// row of field names) format.
+func readRecords(r io.Reader) ([]record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return readCSV(strings.NewReader(trimmed))
+	}
+	if strings.HasPrefix(trimmed, "{") {
+		rec := record{}
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("reading json: %w", err)
+		}
+		return []record{rec}, nil
+	}
+	records := []record{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("reading json: %w", err)
+	}
+	return records, nil
+}
+
+func readCSV(r io.Reader) ([]record, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("reading csv: missing header row")
+	}
+	header := rows[0]
+	records := make([]record, 0, len(rows)-1)
+	for k, row := range rows[1:] {
+		rec := record{}
+		for c, s := range row {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("reading csv: record %d: field %q: %w", k, header[c], err)
+			}
+			rec[strings.TrimSpace(header[c])] = v
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// writeRecords as a JSON array of objects or CSV with a header row. Values
+// are written in the order of columns.
+func writeRecords(w io.Writer, format string, columns []string, records []record) error {
+	if format == "csv" {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		row := make([]string, len(columns))
+		for _, rec := range records {
+			for c, name := range columns {
+				row[c] = strconv.FormatFloat(rec[name], 'f', -1, 64)
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	b := strings.Builder{}
+	b.WriteString("[")
+	for k, rec := range records {
+		if k > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  {")
+		for c, name := range columns {
+			if c > 0 {
+				b.WriteString(", ")
+			}
+			v, err := json.Marshal(rec[name])
+			if err != nil {
+				return fmt.Errorf("writing json: %s: %w", name, err)
+			}
+			fmt.Fprintf(&b, "%q: %s", name, v)
+		}
+		b.WriteString("}")
+	}
+	b.WriteString("\n]\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
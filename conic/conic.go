@@ -0,0 +1,113 @@
+// Package conic samples the conic section of an orbit into a polyline for
+// rendering.
+package conic
+
+import (
+	"math"
+
+	"github.com/wafer-bw/gorbit/frames"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+const (
+	segments = 16 // initial segments before subdivision
+	maxDepth = 20 // maximum subdivisions of an initial segment
+)
+
+// Path polyline tracing the orbit described by the Keplerian Orbital
+// Elements relative to the primary body.
+//
+// a:    semi-major axis                 (m),
+// e:    eccentricity,
+// w:    argument of periapsis           (rad),
+// lan:  longitude of ascending node     (rad),
+// i:    inclination                     (rad),
+// rmax: maximum radius of hyperbolas    (m),
+// tol:  maximum chord error             (m).
+//
+// Elliptic orbits (e < 1, a > 0) are traced in full starting and ending at
+// periapsis. Hyperbolic orbits (e > 1, a < 0) are traced from the inbound
+// to the outbound point at distance rmax from the primary body and rmax is
+// ignored for elliptic orbits. Parabolic orbits are not supported.
+//
+// Segments are subdivided until no point of the conic between their ends
+// is further than tol from the chord so points are densest where the
+// curvature is greatest which is near periapsis.
+//
+// Returns nil if the elements do not describe an ellipse or hyperbola, if
+// rmax is less than the periapsis of a hyperbola or if tol is not positive.
+func Path(a, e, w, lan, i, rmax, tol float64) []f64.Vec3 {
+	p := a * (1 - e*e)
+	if !(p > 0) || !(tol > 0) || e == 1 {
+		return nil
+	}
+
+	lo, hi := float64(0), 2*math.Pi
+	if e > 1 {
+		c := (p/rmax - 1) / e
+		if !(c < 1) {
+			return nil
+		}
+		hi = math.Acos(c)
+		lo = -hi
+	}
+
+	s := sampler{
+		p:     p,
+		e:     e,
+		xAxis: frames.PerifocalToInertial(f64.Vec3{1, 0, 0}, w, lan, i),
+		yAxis: frames.PerifocalToInertial(f64.Vec3{0, 1, 0}, w, lan, i),
+		tol:   tol,
+	}
+	first := s.point(lo)
+	s.points = append(s.points, first)
+	prev := first
+	for k := 1; k <= segments; k++ {
+		nu0 := lo + (hi-lo)*float64(k-1)/segments
+		nu1 := lo + (hi-lo)*float64(k)/segments
+		next := s.point(nu1)
+		s.subdivide(nu0, nu1, prev, next, 0)
+		prev = next
+	}
+	if e < 1 {
+		s.points[len(s.points)-1] = first
+	}
+	return s.points
+}
+
+type sampler struct {
+	p, e         float64
+	xAxis, yAxis f64.Vec3 // perifocal axes in the inertial frame
+	tol          float64
+	points       []f64.Vec3
+}
+
+func (s *sampler) point(nu float64) f64.Vec3 {
+	sin, cos := math.Sincos(nu)
+	r := s.p / (1 + s.e*cos)
+	return vec3.Add(vec3.MulScalar(s.xAxis, r*cos), vec3.MulScalar(s.yAxis, r*sin))
+}
+
+// subdivide the segment between true anomalies nu0 and nu1 appending every
+// point after x0 up to and including x1.
+func (s *sampler) subdivide(nu0, nu1 float64, x0, x1 f64.Vec3, depth int) {
+	mid := (nu0 + nu1) / 2
+	xm := s.point(mid)
+	if depth < maxDepth && distance(xm, x0, x1) > s.tol {
+		s.subdivide(nu0, mid, x0, xm, depth+1)
+		s.subdivide(mid, nu1, xm, x1, depth+1)
+		return
+	}
+	s.points = append(s.points, x1)
+}
+
+// distance from x to the line through a and b.
+func distance(x, a, b f64.Vec3) float64 {
+	ab := vec3.Sub(b, a)
+	d := vec3.Magnitude(ab)
+	if d == 0 {
+		return vec3.Magnitude(vec3.Sub(x, a))
+	}
+	return vec3.Magnitude(vec3.Cross(vec3.Sub(x, a), ab)) / d
+}
@@ -0,0 +1,57 @@
+package conic_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/conic"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/vec3"
+)
+
+func TestPath(t *testing.T) {
+	t.Run("succeed in tracing a closed circle within tolerance", func(t *testing.T) {
+		r, tol := 7000e3, 100.0
+		pts := conic.Path(r, 0, 0, 0, 0, 0, tol)
+		require.Equal(t, pts[0], pts[len(pts)-1])
+		for k := 1; k < len(pts); k++ {
+			require.Equal(t, "7000", fmt.Sprintf("%.0f", vec3.Magnitude(pts[k])/1000))
+			chord := vec3.Magnitude(vec3.Sub(pts[k], pts[k-1]))
+			require.LessOrEqual(t, r-math.Sqrt(r*r-chord*chord/4), tol)
+		}
+	})
+	t.Run("succeed in sampling densest near periapsis", func(t *testing.T) {
+		a, e := 20000e3, 0.7
+		pts := conic.Path(a, e, 0.4, 1.2, 0.6, 0, 1000)
+		near, far := 0, 0
+		for _, x := range pts {
+			r := vec3.Magnitude(x)
+			require.GreaterOrEqual(t, r, gravity.Periapsis(a, e)*(1-1e-9))
+			require.LessOrEqual(t, r, gravity.Apoapsis(a, e)*(1+1e-9))
+			if r < a {
+				near++
+			} else {
+				far++
+			}
+		}
+		require.Greater(t, near, far)
+	})
+	t.Run("succeed in bounding a hyperbola", func(t *testing.T) {
+		a, e, rmax := -10000e3, 1.5, 100000e3
+		pts := conic.Path(a, e, 0, 0, 0, rmax, 1000)
+		require.Equal(t, "100000", fmt.Sprintf("%.0f", vec3.Magnitude(pts[0])/1000))
+		require.Equal(t, "100000", fmt.Sprintf("%.0f", vec3.Magnitude(pts[len(pts)-1])/1000))
+		rmin := math.MaxFloat64
+		for _, x := range pts {
+			rmin = math.Min(rmin, vec3.Magnitude(x))
+		}
+		require.Equal(t, "5000", fmt.Sprintf("%.0f", rmin/1000))
+	})
+	t.Run("fail on invalid elements", func(t *testing.T) {
+		require.Nil(t, conic.Path(7000e3, 1.5, 0, 0, 0, 1e9, 100))
+		require.Nil(t, conic.Path(-7000e3, 1.5, 0, 0, 0, 1e3, 100))
+		require.Nil(t, conic.Path(7000e3, 0.1, 0, 0, 0, 0, 0))
+	})
+}
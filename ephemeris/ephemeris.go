@@ -0,0 +1,151 @@
+package ephemeris
+
+import (
+	"math"
+
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/epoch"
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+// Planet of the solar system.
+type Planet int
+
+const (
+	Mercury Planet = iota
+	Venus
+	Earth // Earth-Moon barycenter
+	Mars
+	Jupiter
+	Saturn
+	Uranus
+	Neptune
+)
+
+// Planets in order of distance from the Sun.
+var Planets = []Planet{Mercury, Venus, Earth, Mars, Jupiter, Saturn, Uranus, Neptune}
+
+// keplerian elements at J2000 and their rates per Julian century.
+//
+// a:   semi-major axis               (au),
+// e:   eccentricity                  (0-1),
+// i:   inclination                   (deg),
+// l:   mean longitude                (deg),
+// lp:  longitude of perihelion       (deg),
+// lan: longitude of ascending node   (deg).
+type keplerian struct {
+	a, e, i, l, lp, lan                   float64
+	aDot, eDot, iDot, lDot, lpDot, lanDot float64
+}
+
+// https://ssd.jpl.nasa.gov/planets/approx_pos.html (Table 1, 1800 AD - 2050 AD)
+var table = [...]keplerian{
+	Mercury: {
+		0.38709927, 0.20563593, 7.00497902, 252.25032350, 77.45779628, 48.33076593,
+		0.00000037, 0.00001906, -0.00594749, 149472.67411175, 0.16047689, -0.12534081,
+	},
+	Venus: {
+		0.72333566, 0.00677672, 3.39467605, 181.97909950, 131.60246718, 76.67984255,
+		0.00000390, -0.00004107, -0.00078890, 58517.81538729, 0.00268329, -0.27769418,
+	},
+	Earth: {
+		1.00000261, 0.01671123, -0.00001531, 100.46457166, 102.93768193, 0.0,
+		0.00000562, -0.00004392, -0.01294668, 35999.37244981, 0.32327364, 0.0,
+	},
+	Mars: {
+		1.52371034, 0.09339410, 1.84969142, -4.55343205, -23.94362959, 49.55953891,
+		0.00001847, 0.00007882, -0.00813131, 19140.30268499, 0.44441088, -0.29257343,
+	},
+	Jupiter: {
+		5.20288700, 0.04838624, 1.30439695, 34.39644051, 14.72847983, 100.47390909,
+		-0.00011607, -0.00013253, -0.00183714, 3034.74612775, 0.21252668, 0.20469106,
+	},
+	Saturn: {
+		9.53667594, 0.05386179, 2.48599187, 49.95424423, 92.59887831, 113.66242448,
+		-0.00125060, -0.00050991, 0.00193609, 1222.49362201, -0.41897216, -0.28867794,
+	},
+	Uranus: {
+		19.18916464, 0.04725744, 0.77263783, 313.23810451, 170.95427630, 74.01692503,
+		-0.00196176, -0.00004397, -0.00242939, 428.48202785, 0.40805281, 0.04240589,
+	},
+	Neptune: {
+		30.06992276, 0.00859048, 1.77004347, -55.12002969, 44.96476227, 131.78422574,
+		0.00026291, 0.00005105, 0.00035372, 218.45945325, -0.32241464, -0.00508664,
+	},
+}
+
+// String name of the planet.
+func (p Planet) String() string {
+	return p.Body().Name
+}
+
+// Body physical constants of the planet.
+func (p Planet) Body() bodies.Body {
+	return bodies.Planets[p]
+}
+
+// Mu standard gravitational parameter of the planet's heliocentric orbit
+// (m^3/s^2). Earth follows the Earth-Moon barycenter so the Moon is
+// included. The satellites of the other planets are ignored since they
+// add less than 3e-4 of the planet's GM.
+func (p Planet) Mu() float64 {
+	if p == Earth {
+		return bodies.Sun.GM + bodies.Earth.GM + bodies.Moon.GM
+	}
+	return bodies.Sun.GM + p.Body().GM
+}
+
+// OrbitalElements of a planet's heliocentric orbit at a Julian Date.
+//
+// accepts:
+// p:  planet,
+// jd: TDB Julian Date (days).
+//
+// returns:
+// a:   semi-major axis                  (m),
+// e:   eccentricity                     (0-1),
+// w:   argument of periapsis            (rad),
+// lan: longitude of ascending node      (rad),
+// i:   inclination                      (rad),
+// m:   mean anomaly                     (rad).
+//
+// Elements are relative to the J2000 mean ecliptic and equinox and are
+// JPL's approximate elements with linear secular rates which are valid
+// from 1800 AD to 2050 AD. Errors are in the order of an arc minute for
+// the inner planets and up to ten arc minutes for Saturn.
+//
+// https://ssd.jpl.nasa.gov/planets/approx_pos.html
+func OrbitalElements(p Planet, jd float64) (a, e, w, lan, i, m float64) {
+	k := table[p]
+	t := epoch.JulianCenturies(jd)
+
+	a = (k.a + k.aDot*t) * gravity.AU
+	e = k.e + k.eDot*t
+	i = gravity.Radians(k.i + k.iDot*t)
+	l := k.l + k.lDot*t
+	lp := k.lp + k.lpDot*t
+	lan = gravity.Radians(k.lan + k.lanDot*t)
+	w = gravity.Radians(lp) - lan
+	m = gravity.Radians(math.Mod(l-lp, 360))
+
+	return
+}
+
+// StateVectors of a planet relative to the Sun at a Julian Date.
+//
+// accepts:
+// p:  planet,
+// jd: TDB Julian Date (days).
+//
+// returns:
+// r:  heliocentric position (m),
+// v:  heliocentric velocity (m/s).
+//
+// Vectors are relative to the J2000 mean ecliptic and equinox, use
+// frames.EclipticToEquatorial to rotate them into the equatorial frame.
+// See OrbitalElements for more details.
+func StateVectors(p Planet, jd float64) (f64.Vec3, f64.Vec3) {
+	a, e, w, lan, i, m := OrbitalElements(p, jd)
+	return gravity.StateVectorsMu(a, e, w, lan, i, m, 0, p.Mu())
+}
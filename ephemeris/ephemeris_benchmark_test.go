@@ -0,0 +1,26 @@
+package ephemeris_test
+
+import (
+	"testing"
+
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/ephemeris"
+)
+
+func BenchmarkLagrange(b *testing.B) {
+	f := ephemeris.Kepler(7000e3, 0.1, 0.3, 0.5, 0.9, 0, bodies.Earth.GM)
+	tb := ephemeris.Tabulate(f, 0, 86400, 60)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tb.Lagrange(float64(i%86400), 8)
+	}
+}
+
+func BenchmarkHermite(b *testing.B) {
+	f := ephemeris.Kepler(7000e3, 0.1, 0.3, 0.5, 0.9, 0, bodies.Earth.GM)
+	tb := ephemeris.Tabulate(f, 0, 86400, 60)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tb.Hermite(float64(i%86400), 4)
+	}
+}
@@ -0,0 +1,49 @@
+package ephemeris_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/ephemeris"
+	"github.com/wafer-bw/gorbit/epoch"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/vec3"
+)
+
+func TestMu(t *testing.T) {
+	t.Run("succeed in including the moon for the earth-moon barycenter", func(t *testing.T) {
+		require.Equal(t, bodies.Sun.GM+bodies.Earth.GM+bodies.Moon.GM, ephemeris.Earth.Mu())
+	})
+	t.Run("succeed in adding the planet to the sun", func(t *testing.T) {
+		require.Equal(t, bodies.Sun.GM+bodies.Mars.GM, ephemeris.Mars.Mu())
+	})
+}
+
+func TestStateVectors(t *testing.T) {
+	t.Run("succeed in calculating the position of earth at J2000", func(t *testing.T) {
+		r, v := ephemeris.StateVectors(ephemeris.Earth, epoch.J2000)
+		require.Equal(t, "[-0.177 0.967 -0.000]", fmt.Sprintf("%.3f", vec3.DivScalar(r, gravity.AU)))
+		require.Equal(t, "30.29", fmt.Sprintf("%.2f", vec3.Magnitude(v)/1000))
+	})
+	t.Run("succeed in calculating the position of mars at J2000", func(t *testing.T) {
+		r, _ := ephemeris.StateVectors(ephemeris.Mars, epoch.J2000)
+		require.Equal(t, "[1.391 -0.013 -0.034]", fmt.Sprintf("%.3f", vec3.DivScalar(r, gravity.AU)))
+	})
+	t.Run("succeed in returning earth to the same place after a sidereal year", func(t *testing.T) {
+		jd := epoch.J2000 + 3000
+		r1, _ := ephemeris.StateVectors(ephemeris.Earth, jd)
+		r2, _ := ephemeris.StateVectors(ephemeris.Earth, jd+365.256363)
+		require.Less(t, vec3.Magnitude(vec3.Sub(r1, r2)), 1e-4*gravity.AU)
+	})
+	t.Run("succeed in keeping every planet between perihelion and aphelion", func(t *testing.T) {
+		jd := epoch.J2000 + 5000
+		for _, p := range ephemeris.Planets {
+			a, e, _, _, _, _ := ephemeris.OrbitalElements(p, jd)
+			r, _ := ephemeris.StateVectors(p, jd)
+			require.GreaterOrEqual(t, vec3.Magnitude(r), gravity.Periapsis(a, e)*(1-1e-9), p.String())
+			require.LessOrEqual(t, vec3.Magnitude(r), gravity.Apoapsis(a, e)*(1+1e-9), p.String())
+		}
+	})
+}
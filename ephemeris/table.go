@@ -0,0 +1,166 @@
+package ephemeris
+
+import (
+	"math"
+	"sort"
+
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+// MaxPoints used by the interpolators. Larger values are clamped.
+const MaxPoints = 16
+
+// StateFunc returns the position (m) and velocity (m/s) at t seconds.
+type StateFunc func(t float64) (f64.Vec3, f64.Vec3)
+
+// Kepler StateFunc of a two body orbit where t is the time since the epoch
+// of the elements. See gravity.StateVectorsMu for more details.
+func Kepler(a, e, w, lan, i, m0, mu float64) StateFunc {
+	return func(t float64) (f64.Vec3, f64.Vec3) {
+		return gravity.StateVectorsMu(a, e, w, lan, i, m0, t, mu)
+	}
+}
+
+// Entry of an ephemeris table.
+//
+// T: time tag      (s),
+// R: position      (m),
+// V: velocity      (m/s).
+type Entry struct {
+	T float64
+	R f64.Vec3
+	V f64.Vec3
+}
+
+// Table of states in increasing time order.
+//
+// Tables may be generated with Tabulate or built from any other source
+// such as a numerically integrated trajectory or an OEM segment as long as
+// the entries are sorted by T.
+type Table []Entry
+
+// Tabulate f from t0 to t1 (s) inclusive every step (s). The table is
+// empty unless step > 0, t0 and t1 are finite, t1 >= t0 and there are
+// fewer than math.MaxInt32 steps between them.
+func Tabulate(f StateFunc, t0, t1, step float64) Table {
+	n := (t1 - t0) / step
+	if !(step > 0) || !(n >= 0 && n < math.MaxInt32) {
+		return Table{}
+	}
+	tb := make(Table, 0, int(n)+2)
+	for k := 0; k <= int(n); k++ {
+		t := t0 + float64(k)*step
+		r, v := f(t)
+		tb = append(tb, Entry{T: t, R: r, V: v})
+	}
+	if last := tb[len(tb)-1].T; last < t1 {
+		r, v := f(t1)
+		tb = append(tb, Entry{T: t1, R: r, V: v})
+	}
+	return tb
+}
+
+// Lagrange interpolation of the state at t (s) using the points entries
+// nearest t. Positions and velocities are interpolated independently with
+// polynomials of degree points-1.
+//
+// Times outside of the table are extrapolated which quickly loses
+// accuracy. An empty table gives zero vectors.
+//
+// https://en.wikipedia.org/wiki/Lagrange_polynomial
+func (tb Table) Lagrange(t float64, points int) (f64.Vec3, f64.Vec3) {
+	if len(tb) == 0 {
+		return f64.Vec3{}, f64.Vec3{}
+	}
+	w := tb.window(t, points)
+	var z [MaxPoints]float64
+	var cr, cv [MaxPoints]f64.Vec3
+	for k, e := range w {
+		z[k], cr[k], cv[k] = e.T, e.R, e.V
+	}
+	n := len(w)
+	divide(z[:n], cr[:n], nil)
+	divide(z[:n], cv[:n], nil)
+	r, _ := horner(z[:n], cr[:n], t)
+	v, _ := horner(z[:n], cv[:n], t)
+	return r, v
+}
+
+// Hermite interpolation of the state at t (s) using the positions and
+// velocities of the points entries nearest t. The position polynomial has
+// degree 2*points-1 and the velocity is its derivative.
+//
+// Times outside of the table are extrapolated which quickly loses
+// accuracy. An empty table gives zero vectors.
+//
+// https://en.wikipedia.org/wiki/Hermite_interpolation
+func (tb Table) Hermite(t float64, points int) (f64.Vec3, f64.Vec3) {
+	if len(tb) == 0 {
+		return f64.Vec3{}, f64.Vec3{}
+	}
+	w := tb.window(t, points)
+	var z [2 * MaxPoints]float64
+	var c, d [2 * MaxPoints]f64.Vec3
+	for k, e := range w {
+		z[2*k], z[2*k+1] = e.T, e.T
+		c[2*k], c[2*k+1] = e.R, e.R
+		d[2*k], d[2*k+1] = e.V, e.V
+	}
+	n := 2 * len(w)
+	divide(z[:n], c[:n], d[:n])
+	return horner(z[:n], c[:n], t)
+}
+
+// window of up to points entries centered on t from a non-empty table.
+func (tb Table) window(t float64, points int) Table {
+	if points > MaxPoints {
+		points = MaxPoints
+	}
+	if points > len(tb) {
+		points = len(tb)
+	}
+	if points < 1 {
+		points = 1
+	}
+	k := sort.Search(len(tb), func(k int) bool { return tb[k].T > t })
+	start := k - points/2
+	if start < 0 {
+		start = 0
+	}
+	if start > len(tb)-points {
+		start = len(tb) - points
+	}
+	return tb[start : start+points]
+}
+
+// divide c in place into the Newton divided differences of the nodes z.
+// Repeated nodes take their first divided difference from d.
+func divide(z []float64, c, d []f64.Vec3) {
+	for j := 1; j < len(z); j++ {
+		for k := len(z) - 1; k >= j; k-- {
+			if d != nil && z[k] == z[k-j] {
+				c[k] = d[k]
+				continue
+			}
+			dz := z[k] - z[k-j]
+			for x := 0; x < 3; x++ {
+				c[k][x] = (c[k][x] - c[k-1][x]) / dz
+			}
+		}
+	}
+}
+
+// horner evaluates the Newton polynomial and its derivative at t.
+func horner(z []float64, c []f64.Vec3, t float64) (f64.Vec3, f64.Vec3) {
+	n := len(z)
+	p, dp := c[n-1], f64.Vec3{}
+	for k := n - 2; k >= 0; k-- {
+		dt := t - z[k]
+		for x := 0; x < 3; x++ {
+			dp[x] = dp[x]*dt + p[x]
+			p[x] = p[x]*dt + c[k][x]
+		}
+	}
+	return p, dp
+}
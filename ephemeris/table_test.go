@@ -0,0 +1,76 @@
+package ephemeris_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/ephemeris"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+func TestTable(t *testing.T) {
+	a, e := 7000e3, 0.1
+	f := ephemeris.Kepler(a, e, 0.3, 0.5, gravity.Radians(51.6), 0, bodies.Earth.GM)
+	period := gravity.PeriodMu(a, bodies.Earth.GM)
+	tb := ephemeris.Tabulate(f, 0, period, 60)
+
+	t.Run("succeed in tabulating inclusive of the end time", func(t *testing.T) {
+		require.Equal(t, float64(0), tb[0].T)
+		require.Equal(t, period, tb[len(tb)-1].T)
+		for k := 1; k < len(tb); k++ {
+			require.Greater(t, tb[k].T, tb[k-1].T)
+		}
+	})
+	t.Run("succeed in interpolating with lagrange polynomials", func(t *testing.T) {
+		for _, tt := range []float64{30, 1234.5, period/2 + 17, period - 10} {
+			r1, v1 := f(tt)
+			r2, v2 := tb.Lagrange(tt, 8)
+			require.Less(t, vec3.Magnitude(vec3.Sub(r1, r2)), 1e-2)
+			require.Less(t, vec3.Magnitude(vec3.Sub(v1, v2)), 1e-5)
+		}
+	})
+	t.Run("succeed in interpolating with hermite polynomials", func(t *testing.T) {
+		for _, tt := range []float64{30, 1234.5, period/2 + 17, period - 10} {
+			r1, v1 := f(tt)
+			r2, v2 := tb.Hermite(tt, 4)
+			require.Less(t, vec3.Magnitude(vec3.Sub(r1, r2)), 1e-2)
+			require.Less(t, vec3.Magnitude(vec3.Sub(v1, v2)), 1e-5)
+		}
+	})
+	t.Run("succeed in reproducing table entries exactly", func(t *testing.T) {
+		e := tb[10]
+		r, v := tb.Hermite(e.T, 4)
+		require.Less(t, vec3.Magnitude(vec3.Sub(e.R, r)), 1e-6)
+		require.Less(t, vec3.Magnitude(vec3.Sub(e.V, v)), 1e-9)
+	})
+	t.Run("succeed in clamping the number of points", func(t *testing.T) {
+		short := tb[:3]
+		r1, _ := f(90)
+		r2, _ := short.Lagrange(90, 100)
+		require.Less(t, vec3.Magnitude(vec3.Sub(r1, r2)), float64(1000))
+	})
+	t.Run("succeed in tabulating nothing for an invalid range or step", func(t *testing.T) {
+		require.Empty(t, ephemeris.Tabulate(f, 0, period, 0))
+		require.Empty(t, ephemeris.Tabulate(f, 0, period, -60))
+		require.Empty(t, ephemeris.Tabulate(f, period, 0, 60))
+		require.Empty(t, ephemeris.Tabulate(f, 0, math.NaN(), 60))
+		require.Empty(t, ephemeris.Tabulate(f, 0, math.Inf(1), 60))
+		require.Empty(t, ephemeris.Tabulate(f, math.Inf(-1), 0, 60))
+		require.Empty(t, ephemeris.Tabulate(f, -1e300, 1e300, 60))
+		require.Len(t, ephemeris.Tabulate(f, 60, 60, 60), 1)
+	})
+	t.Run("succeed in interpolating an empty table as zero", func(t *testing.T) {
+		for _, empty := range []ephemeris.Table{nil, {}} {
+			r, v := empty.Lagrange(90, 8)
+			require.Equal(t, f64.Vec3{}, r)
+			require.Equal(t, f64.Vec3{}, v)
+			r, v = empty.Hermite(90, 4)
+			require.Equal(t, f64.Vec3{}, r)
+			require.Equal(t, f64.Vec3{}, v)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package epoch
+
+import (
+	"bufio"
+	_ "embed" // leap second table
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	J2000          float64 = 2451545.0 // Julian Date of the J2000.0 epoch (TT)
+	MJDOffset      float64 = 2400000.5 // Julian Date of the Modified Julian Date epoch
+	UnixEpoch      float64 = 2440587.5 // Julian Date of the Unix epoch
+	SecondsPerDay  float64 = 86400
+	DaysPerCentury float64 = 36525
+	TTMinusTAI     float64 = 32.184 // (s)
+)
+
+//go:embed leapseconds.txt
+var leapSecondsTable string
+
+type leapSecond struct {
+	jd     float64 // UTC Julian Date the offset takes effect
+	offset float64 // TAI-UTC (s)
+}
+
+var leapSeconds = parseLeapSeconds(leapSecondsTable)
+
+// JulianDate from a time.
+//
+// The time is converted to UTC first so the result is a UTC Julian Date.
+//
+// https://en.wikipedia.org/wiki/Julian_day
+func JulianDate(t time.Time) float64 {
+	t = t.UTC()
+	return UnixEpoch + (float64(t.Unix())+float64(t.Nanosecond())/1e9)/SecondsPerDay
+}
+
+// Time from a Julian Date.
+//
+// jd: Julian Date (days).
+//
+// The result is in UTC and rounded to the nearest microsecond. Note that a
+// float64 Julian Date only resolves to roughly 40 microseconds.
+func Time(jd float64) time.Time {
+	days := jd - UnixEpoch
+	sec := math.Floor(days * SecondsPerDay)
+	nsec := math.Round((days*SecondsPerDay-sec)*1e6) * 1e3
+	return time.Unix(int64(sec), int64(nsec)).UTC()
+}
+
+// ModifiedJulianDate from a time.
+//
+// https://en.wikipedia.org/wiki/Julian_day#Variants
+func ModifiedJulianDate(t time.Time) float64 {
+	return JulianDate(t) - MJDOffset
+}
+
+// TimeFromModifiedJulianDate from a Modified Julian Date.
+//
+// mjd: Modified Julian Date (days).
+//
+// See Time for more details.
+func TimeFromModifiedJulianDate(mjd float64) time.Time {
+	return Time(mjd + MJDOffset)
+}
+
+// JulianCenturies since J2000.0.
+//
+// jd: Julian Date (days).
+func JulianCenturies(jd float64) float64 {
+	return (jd - J2000) / DaysPerCentury
+}
+
+// Seconds elapsed from jd0 to jd1.
+//
+// jd1: Julian Date (days),
+// jd0: Julian Date of the epoch (days).
+//
+// Both dates must be in the same time scale. The result is suitable for
+// the t argument of gravity.StateVectors when jd0 is the element epoch.
+func Seconds(jd1, jd0 float64) float64 {
+	return (jd1 - jd0) * SecondsPerDay
+}
+
+// TAIMinusUTC offset (s) at a UTC Julian Date using the embedded leap second
+// table.
+//
+// jd: UTC Julian Date (days).
+//
+// Dates before 1972 are not covered by the table and use the 1972 offset.
+//
+// https://en.wikipedia.org/wiki/Leap_second
+func TAIMinusUTC(jd float64) float64 {
+	offset := leapSeconds[0].offset
+	for _, ls := range leapSeconds {
+		if jd < ls.jd {
+			break
+		}
+		offset = ls.offset
+	}
+	return offset
+}
+
+// UTCToTAI Julian Date.
+//
+// jd: UTC Julian Date (days).
+func UTCToTAI(jd float64) float64 {
+	return jd + TAIMinusUTC(jd)/SecondsPerDay
+}
+
+// TAIToUTC Julian Date.
+//
+// jd: TAI Julian Date (days).
+func TAIToUTC(jd float64) float64 {
+	utc := jd - TAIMinusUTC(jd)/SecondsPerDay
+	return jd - TAIMinusUTC(utc)/SecondsPerDay
+}
+
+// TAIToTT Julian Date.
+//
+// jd: TAI Julian Date (days).
+func TAIToTT(jd float64) float64 {
+	return jd + TTMinusTAI/SecondsPerDay
+}
+
+// TTToTAI Julian Date.
+//
+// jd: TT Julian Date (days).
+func TTToTAI(jd float64) float64 {
+	return jd - TTMinusTAI/SecondsPerDay
+}
+
+// UTCToTT Julian Date.
+//
+// jd: UTC Julian Date (days).
+func UTCToTT(jd float64) float64 {
+	return TAIToTT(UTCToTAI(jd))
+}
+
+// TTToUTC Julian Date.
+//
+// jd: TT Julian Date (days).
+func TTToUTC(jd float64) float64 {
+	return TAIToUTC(TTToTAI(jd))
+}
+
+// TTToTDB Julian Date.
+//
+// jd: TT Julian Date (days).
+//
+// Uses the two term periodic approximation of TDB-TT which is accurate to
+// about 30 microseconds.
+//
+// https://en.wikipedia.org/wiki/Barycentric_Dynamical_Time
+func TTToTDB(jd float64) float64 {
+	return jd + tdbMinusTT(jd)/SecondsPerDay
+}
+
+// TDBToTT Julian Date.
+//
+// jd: TDB Julian Date (days).
+//
+// See TTToTDB for more details.
+func TDBToTT(jd float64) float64 {
+	return jd - tdbMinusTT(jd)/SecondsPerDay
+}
+
+// tdbMinusTT (s) where g is the mean anomaly of the Earth's orbit.
+func tdbMinusTT(jd float64) float64 {
+	g := (357.53 + 0.98560028*(jd-J2000)) * math.Pi / 180
+	return 0.001657*math.Sin(g) + 0.000014*math.Sin(2*g)
+}
+
+func parseLeapSeconds(table string) []leapSecond {
+	lss := []leapSecond{}
+	scanner := bufio.NewScanner(strings.NewReader(table))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			panic("epoch: malformed leap second table line: " + line)
+		}
+		date, err := time.Parse("2006-01-02", fields[0])
+		if err != nil {
+			panic("epoch: malformed leap second table date: " + err.Error())
+		}
+		offset, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			panic("epoch: malformed leap second table offset: " + err.Error())
+		}
+		lss = append(lss, leapSecond{jd: JulianDate(date), offset: offset})
+	}
+	return lss
+}
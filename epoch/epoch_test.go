@@ -0,0 +1,60 @@
+package epoch_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/epoch"
+)
+
+func TestJulianDate(t *testing.T) {
+	t.Run("succeed in calculating the julian date of J2000", func(t *testing.T) {
+		jd := epoch.JulianDate(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC))
+		require.Equal(t, "2451545.000000", fmt.Sprintf("%.6f", jd))
+	})
+	t.Run("succeed in calculating the julian date of a non UTC time", func(t *testing.T) {
+		loc := time.FixedZone("UTC-5", -5*60*60)
+		jd := epoch.JulianDate(time.Date(2000, 1, 1, 7, 0, 0, 0, loc))
+		require.Equal(t, "2451545.000000", fmt.Sprintf("%.6f", jd))
+	})
+	t.Run("succeed in calculating the modified julian date", func(t *testing.T) {
+		mjd := epoch.ModifiedJulianDate(time.Date(1858, 11, 17, 0, 0, 0, 0, time.UTC))
+		require.Equal(t, "0.000000", fmt.Sprintf("%.6f", mjd))
+	})
+	t.Run("succeed in converting a julian date back to a time", func(t *testing.T) {
+		want := time.Date(2023, 3, 14, 15, 9, 26, 535000000, time.UTC)
+		require.Equal(t, want, epoch.Time(epoch.JulianDate(want)).Round(time.Millisecond))
+		require.Equal(t, want, epoch.TimeFromModifiedJulianDate(epoch.ModifiedJulianDate(want)).Round(time.Millisecond))
+	})
+	t.Run("succeed in calculating julian centuries and seconds", func(t *testing.T) {
+		require.Equal(t, "1.000000", fmt.Sprintf("%.6f", epoch.JulianCenturies(epoch.J2000+36525)))
+		require.Equal(t, "43200.000", fmt.Sprintf("%.3f", epoch.Seconds(epoch.J2000+0.5, epoch.J2000)))
+	})
+}
+
+func TestTimeScales(t *testing.T) {
+	t.Run("succeed in looking up leap seconds", func(t *testing.T) {
+		require.Equal(t, float64(10), epoch.TAIMinusUTC(epoch.JulianDate(time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC))))
+		require.Equal(t, float64(32), epoch.TAIMinusUTC(epoch.J2000))
+		require.Equal(t, float64(36), epoch.TAIMinusUTC(epoch.JulianDate(time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC))))
+		require.Equal(t, float64(37), epoch.TAIMinusUTC(epoch.JulianDate(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC))))
+	})
+	t.Run("succeed in converting UTC to TT", func(t *testing.T) {
+		utc := epoch.JulianDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		tt := epoch.UTCToTT(utc)
+		require.Equal(t, "69.184", fmt.Sprintf("%.3f", epoch.Seconds(tt, utc)))
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", epoch.Seconds(epoch.TTToUTC(tt), utc)))
+	})
+	t.Run("succeed in converting TAI to UTC across a leap second", func(t *testing.T) {
+		utc := epoch.JulianDate(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC))
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", epoch.Seconds(epoch.TAIToUTC(epoch.UTCToTAI(utc)), utc)))
+	})
+	t.Run("succeed in converting TT to TDB", func(t *testing.T) {
+		tt := epoch.J2000
+		tdb := epoch.TTToTDB(tt)
+		require.Equal(t, "-0.000", fmt.Sprintf("%.3f", epoch.Seconds(tdb, tt)))
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", epoch.Seconds(epoch.TDBToTT(tdb), tt)))
+	})
+}
@@ -0,0 +1,157 @@
+// Package events locates events such as apsides, node crossings and
+// radius crossings along an orbit or propagated trajectory.
+//
+// Events are zero crossings of a function of the state. The state is
+// sampled every step and each bracketed crossing is refined by bisection
+// until it is known to within a time tolerance. Any ephemeris.StateFunc
+// may be searched, such as ephemeris.Kepler for a two body orbit or an
+// interpolated ephemeris.Table for a propagated trajectory.
+package events
+
+import (
+	"math"
+
+	"github.com/wafer-bw/gorbit/ephemeris"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+// Direction of the zero crossings which trigger an event.
+type Direction int
+
+const (
+	Rising  Direction = iota // negative to positive
+	Falling                  // positive to negative
+	Either                   // either way
+)
+
+// Event which occurs when G crosses zero in Direction.
+//
+// G:         function of position (m) and velocity (m/s),
+// Direction: direction of the zero crossings which trigger the event.
+type Event struct {
+	G         func(r, v f64.Vec3) float64
+	Direction Direction
+}
+
+var (
+	// Periapsis where the radial velocity turns from inward to outward.
+	Periapsis = Event{G: radialVelocity, Direction: Rising}
+	// Apoapsis where the radial velocity turns from outward to inward.
+	Apoapsis = Event{G: radialVelocity, Direction: Falling}
+	// AscendingNode where the orbit crosses the x-y plane heading north.
+	AscendingNode = Event{G: height, Direction: Rising}
+	// DescendingNode where the orbit crosses the x-y plane heading south.
+	DescendingNode = Event{G: height, Direction: Falling}
+)
+
+// Radius event when the distance from the primary body crosses radius (m)
+// in either direction.
+func Radius(radius float64) Event {
+	return Event{
+		G:         func(r, v f64.Vec3) float64 { return vec3.Magnitude(r) - radius },
+		Direction: Either,
+	}
+}
+
+// TrueAnomaly event when the true anomaly reaches nu (rad).
+//
+// nu: target true anomaly               (rad),
+// mu: standard gravitational parameter  (m^3/s^2).
+//
+// The true anomaly is measured from the eccentricity vector of the
+// osculating orbit so the orbit must not be circular.
+func TrueAnomaly(nu, mu float64) Event {
+	return Event{
+		G: func(r, v f64.Vec3) float64 {
+			return math.Remainder(trueAnomaly(r, v, mu)-nu, 2*math.Pi)
+		},
+		Direction: Rising,
+	}
+}
+
+// Next time after t0 and no later than t1 (s) the event occurs.
+//
+// f:    states to search,
+// t0:   time to search from (s),
+// t1:   time to search until (s),
+// step: time between samples (s),
+// tol:  time tolerance of the result (s).
+//
+// The step must be shorter than the time between consecutive events to
+// not miss any. The returned time is within tol after the event. An
+// event exactly at t0 is not reported. ok is false if there is no event
+// before t1.
+func (e Event) Next(f ephemeris.StateFunc, t0, t1, step, tol float64) (t float64, ok bool) {
+	if !(step > 0) || !(tol > 0) {
+		return 0, false
+	}
+	g := func(t float64) float64 {
+		r, v := f(t)
+		return e.G(r, v)
+	}
+	lo, glo := t0, g(t0)
+	for lo < t1 {
+		hi := math.Min(lo+step, t1)
+		ghi := g(hi)
+		if !e.crosses(glo, ghi) {
+			lo, glo = hi, ghi
+			continue
+		}
+		for hi-lo > tol {
+			mid := (lo + hi) / 2
+			gmid := g(mid)
+			if e.crosses(glo, gmid) {
+				hi = mid
+			} else {
+				lo, glo = mid, gmid
+			}
+		}
+		return hi, true
+	}
+	return 0, false
+}
+
+// All times after t0 and no later than t1 (s) the event occurs. See Next
+// for more details.
+func (e Event) All(f ephemeris.StateFunc, t0, t1, step, tol float64) []float64 {
+	times := []float64{}
+	for {
+		t, ok := e.Next(f, t0, t1, step, tol)
+		if !ok {
+			return times
+		}
+		times = append(times, t)
+		t0 = t
+	}
+}
+
+func (e Event) crosses(g0, g1 float64) bool {
+	rising := g0 < 0 && g1 >= 0
+	falling := g0 > 0 && g1 <= 0
+	switch e.Direction {
+	case Rising:
+		// Functions that wrap such as TrueAnomaly jump from positive to
+		// negative and must not be mistaken for crossings.
+		return rising
+	case Falling:
+		return falling
+	}
+	return rising || falling
+}
+
+func radialVelocity(r, v f64.Vec3) float64 {
+	return vec3.Dot(r, v)
+}
+
+func height(r, v f64.Vec3) float64 {
+	return r[2]
+}
+
+// trueAnomaly (rad) in (-Pi, Pi] of the osculating orbit.
+func trueAnomaly(r, v f64.Vec3, mu float64) float64 {
+	h := vec3.Cross(r, v)
+	e := vec3.Sub(vec3.DivScalar(vec3.Cross(v, h), mu), vec3.Normalize(r))
+	y := vec3.Dot(h, vec3.Cross(e, r)) / vec3.Magnitude(h)
+	return math.Atan2(y, vec3.Dot(e, r))
+}
@@ -0,0 +1,91 @@
+package events_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/ephemeris"
+	"github.com/wafer-bw/gorbit/events"
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+const (
+	a, e, w, lan, i, m0 = 8000e3, 0.2, 1.0, 0.3, 0.5, 1.0
+	tol                 = 1e-3
+	step                = 60
+)
+
+var (
+	mu     = bodies.Earth.GM
+	n      = math.Sqrt(mu / (a * a * a))
+	orbit  = ephemeris.Kepler(a, e, w, lan, i, m0, mu)
+	period = gravity.PeriodMu(a, mu)
+)
+
+// timeOfTrueAnomaly after epoch of the first pass through nu.
+func timeOfTrueAnomaly(nu float64) float64 {
+	eca := 2 * math.Atan(math.Sqrt((1-e)/(1+e))*math.Tan(nu/2))
+	m := eca - e*math.Sin(eca)
+	return math.Mod(m-m0+4*math.Pi, 2*math.Pi) / n
+}
+
+func TestNext(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		event events.Event
+		want  float64
+	}{
+		{"periapsis", events.Periapsis, (2*math.Pi - m0) / n},
+		{"apoapsis", events.Apoapsis, (math.Pi - m0) / n},
+		{"ascending node", events.AscendingNode, timeOfTrueAnomaly(2*math.Pi - w)},
+		{"descending node", events.DescendingNode, timeOfTrueAnomaly(math.Pi - w)},
+		{"true anomaly", events.TrueAnomaly(2.5, mu), timeOfTrueAnomaly(2.5)},
+		{"true anomaly behind", events.TrueAnomaly(-1, mu), timeOfTrueAnomaly(-1)},
+		{"radius", events.Radius(a), (math.Pi/2 - e - m0) / n},
+	} {
+		t.Run("succeed in finding the next "+tc.name, func(t *testing.T) {
+			got, ok := tc.event.Next(orbit, 0, period, step, tol)
+			require.True(t, ok)
+			require.GreaterOrEqual(t, got, tc.want)
+			require.LessOrEqual(t, got, tc.want+tol)
+		})
+	}
+	t.Run("succeed in not reporting an event at the start", func(t *testing.T) {
+		tp := (2*math.Pi - m0) / n
+		got, ok := events.Periapsis.Next(orbit, tp+tol, tp+2*period, step, tol)
+		require.True(t, ok)
+		require.Equal(t, fmt.Sprintf("%.1f", tp+period), fmt.Sprintf("%.1f", got))
+	})
+	t.Run("fail when the event is after the end", func(t *testing.T) {
+		_, ok := events.Periapsis.Next(orbit, 0, 60, step, tol)
+		require.False(t, ok)
+	})
+	t.Run("fail for an equatorial orbit without nodes", func(t *testing.T) {
+		flat := func(t float64) (f64.Vec3, f64.Vec3) {
+			return f64.Vec3{math.Cos(t), math.Sin(t), 0}, f64.Vec3{-math.Sin(t), math.Cos(t), 0}
+		}
+		_, ok := events.AscendingNode.Next(flat, 0, 10, 0.1, tol)
+		require.False(t, ok)
+	})
+	t.Run("succeed in searching an interpolated trajectory", func(t *testing.T) {
+		table := ephemeris.Tabulate(orbit, 0, period, 120)
+		interp := func(t float64) (f64.Vec3, f64.Vec3) { return table.Hermite(t, 6) }
+		got, ok := events.Apoapsis.Next(interp, 0, period, step, tol)
+		require.True(t, ok)
+		require.Equal(t, fmt.Sprintf("%.2f", (math.Pi-m0)/n), fmt.Sprintf("%.2f", got))
+	})
+}
+
+func TestAll(t *testing.T) {
+	t.Run("succeed in finding every radius crossing", func(t *testing.T) {
+		got := events.Radius(a).All(orbit, 0, 3*period, step, tol)
+		require.Len(t, got, 6)
+		for k := 2; k < len(got); k++ {
+			require.Equal(t, fmt.Sprintf("%.2f", period), fmt.Sprintf("%.2f", got[k]-got[k-2]))
+		}
+	})
+}
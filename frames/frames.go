@@ -0,0 +1,230 @@
+package frames
+
+import (
+	"math"
+
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+// Obliquity of the ecliptic at J2000 (rad).
+const Obliquity float64 = 23.4392911 * math.Pi / 180
+
+// PerifocalToInertial rotates a vector from the perifocal (PQW) frame into
+// the inertial frame.
+//
+// p:   vector in the perifocal frame,
+// w:   argument of periapsis         (rad),
+// lan: longitude of ascending node   (rad),
+// i:   inclination                   (rad).
+//
+// The perifocal frame has P pointing at periapsis, Q 90 degrees ahead of P
+// in the direction of motion and W along the orbit normal.
+//
+// https://en.wikipedia.org/wiki/Perifocal_coordinate_system
+func PerifocalToInertial(p f64.Vec3, w, lan, i float64) f64.Vec3 {
+	return vec3.MulMat(perifocal(w, lan, i), p)
+}
+
+// InertialToPerifocal rotates a vector from the inertial frame into the
+// perifocal (PQW) frame.
+//
+// x:   vector in the inertial frame,
+// w:   argument of periapsis         (rad),
+// lan: longitude of ascending node   (rad),
+// i:   inclination                   (rad).
+//
+// See PerifocalToInertial for more details.
+func InertialToPerifocal(x f64.Vec3, w, lan, i float64) f64.Vec3 {
+	return vec3.MulMatT(perifocal(w, lan, i), x)
+}
+
+// InertialToRTN rotates a vector from the inertial frame into the
+// radial-transverse-normal (RTN) frame of the state (r, v).
+//
+// x: vector in the inertial frame,
+// r: position relative to primary body (m),
+// v: velocity relative to primary body (m/s).
+//
+// R points along r, N along the angular momentum r x v and T completes the
+// right handed set. This is the same frame commonly referred to as the
+// local-vertical local-horizontal (LVLH) or Hill frame with x radial,
+// y along-track and z cross-track.
+//
+// The rotation rate of the frame is ignored, use InertialToRTNState to
+// transform relative velocities.
+func InertialToRTN(x, r, v f64.Vec3) f64.Vec3 {
+	return vec3.MulMat(rtn(r, v), x)
+}
+
+// RTNToInertial rotates a vector from the radial-transverse-normal (RTN)
+// frame of the state (r, v) into the inertial frame.
+//
+// x: vector in the RTN frame,
+// r: position relative to primary body (m),
+// v: velocity relative to primary body (m/s).
+//
+// See InertialToRTN for more details.
+func RTNToInertial(x, r, v f64.Vec3) f64.Vec3 {
+	return vec3.MulMatT(rtn(r, v), x)
+}
+
+// InertialToRTNState transforms a relative state from the inertial frame
+// into the rotating radial-transverse-normal (RTN) frame of the reference
+// state (r, v).
+//
+// accepts:
+// dr: relative position in the inertial frame   (m),
+// dv: relative velocity in the inertial frame   (m/s),
+// r:  position of the reference relative to primary body (m),
+// v:  velocity of the reference relative to primary body (m/s).
+//
+// returns:
+// relative position in the RTN frame            (m),
+// relative velocity in the RTN frame            (m/s).
+//
+// The frame rotates about N at the angular rate |r x v| / |r|^2 so the
+// omega x dr term is removed from the relative velocity.
+func InertialToRTNState(dr, dv, r, v f64.Vec3) (f64.Vec3, f64.Vec3) {
+	m := rtn(r, v)
+	omega := rtnRate(r, v)
+	return vec3.MulMat(m, dr), vec3.MulMat(m, vec3.Sub(dv, vec3.Cross(omega, dr)))
+}
+
+// RTNToInertialState transforms a relative state from the rotating
+// radial-transverse-normal (RTN) frame of the reference state (r, v) into
+// the inertial frame.
+//
+// accepts:
+// dr: relative position in the RTN frame        (m),
+// dv: relative velocity in the RTN frame        (m/s),
+// r:  position of the reference relative to primary body (m),
+// v:  velocity of the reference relative to primary body (m/s).
+//
+// returns:
+// relative position in the inertial frame       (m),
+// relative velocity in the inertial frame       (m/s).
+//
+// See InertialToRTNState for more details.
+func RTNToInertialState(dr, dv, r, v f64.Vec3) (f64.Vec3, f64.Vec3) {
+	m := rtn(r, v)
+	omega := rtnRate(r, v)
+	x := vec3.MulMatT(m, dr)
+	return x, vec3.Add(vec3.MulMatT(m, dv), vec3.Cross(omega, x))
+}
+
+// InertialToBodyFixed transforms a state from the inertial frame into the
+// frame of a body rotating about the inertial z axis.
+//
+// accepts:
+// r:      position in the inertial frame               (m),
+// v:      velocity in the inertial frame               (m/s),
+// rate:   rotation rate of the body                    (rad/s),
+// t:      time since epoch                             (seconds),
+// theta0: rotation angle of the body at epoch          (rad).
+//
+// returns:
+// r: position in the body-fixed frame                  (m),
+// v: velocity in the body-fixed frame                  (m/s).
+//
+// The body-fixed velocity has the omega x r term removed so a point at rest
+// on the surface of the body has zero velocity.
+//
+// https://en.wikipedia.org/wiki/Rotating_reference_frame
+func InertialToBodyFixed(r, v f64.Vec3, rate, t, theta0 float64) (f64.Vec3, f64.Vec3) {
+	m := rotZ(theta0 + rate*t)
+	omega := f64.Vec3{0, 0, rate}
+	return vec3.MulMat(m, r), vec3.MulMat(m, vec3.Sub(v, vec3.Cross(omega, r)))
+}
+
+// BodyFixedToInertial transforms a state from the frame of a body rotating
+// about the inertial z axis into the inertial frame.
+//
+// accepts:
+// r:      position in the body-fixed frame             (m),
+// v:      velocity in the body-fixed frame             (m/s),
+// rate:   rotation rate of the body                    (rad/s),
+// t:      time since epoch                             (seconds),
+// theta0: rotation angle of the body at epoch          (rad).
+//
+// returns:
+// r: position in the inertial frame                    (m),
+// v: velocity in the inertial frame                    (m/s).
+//
+// See InertialToBodyFixed for more details.
+func BodyFixedToInertial(r, v f64.Vec3, rate, t, theta0 float64) (f64.Vec3, f64.Vec3) {
+	m := rotZ(theta0 + rate*t)
+	omega := f64.Vec3{0, 0, rate}
+	x := vec3.MulMatT(m, r)
+	return x, vec3.Add(vec3.MulMatT(m, v), vec3.Cross(omega, x))
+}
+
+// EclipticToEquatorial rotates a vector from the J2000 mean ecliptic frame
+// into the J2000 mean equatorial frame.
+//
+// x: vector in the ecliptic frame.
+//
+// https://en.wikipedia.org/wiki/Ecliptic_coordinate_system#Rectangular_coordinates
+func EclipticToEquatorial(x f64.Vec3) f64.Vec3 {
+	return vec3.MulMatT(rotX(Obliquity), x)
+}
+
+// EquatorialToEcliptic rotates a vector from the J2000 mean equatorial
+// frame into the J2000 mean ecliptic frame.
+//
+// x: vector in the equatorial frame.
+//
+// See EclipticToEquatorial for more details.
+func EquatorialToEcliptic(x f64.Vec3) f64.Vec3 {
+	return vec3.MulMat(rotX(Obliquity), x)
+}
+
+// perifocal rotation matrix from PQW to inertial.
+func perifocal(w, lan, i float64) f64.Mat3 {
+	sw, cw := math.Sincos(w)
+	sl, cl := math.Sincos(lan)
+	si, ci := math.Sincos(i)
+	return f64.Mat3{
+		cw*cl - sw*ci*sl, -(sw*cl + cw*ci*sl), si * sl,
+		cw*sl + sw*ci*cl, cw*ci*cl - sw*sl, -si * cl,
+		sw * si, cw * si, ci,
+	}
+}
+
+// rtn rotation matrix from inertial to RTN, rows are the R, T & N axes.
+func rtn(r, v f64.Vec3) f64.Mat3 {
+	rhat := vec3.Normalize(r)
+	nhat := vec3.Normalize(vec3.Cross(r, v))
+	that := vec3.Cross(nhat, rhat)
+	return f64.Mat3{
+		rhat[0], rhat[1], rhat[2],
+		that[0], that[1], that[2],
+		nhat[0], nhat[1], nhat[2],
+	}
+}
+
+// rtnRate is the inertial angular velocity of the RTN frame.
+func rtnRate(r, v f64.Vec3) f64.Vec3 {
+	rmag := vec3.Magnitude(r)
+	return vec3.DivScalar(vec3.Cross(r, v), rmag*rmag)
+}
+
+// rotZ rotation matrix from inertial to a frame rotated by theta about z.
+func rotZ(theta float64) f64.Mat3 {
+	s, c := math.Sincos(theta)
+	return f64.Mat3{
+		c, s, 0,
+		-s, c, 0,
+		0, 0, 1,
+	}
+}
+
+// rotX rotation matrix from inertial to a frame rotated by theta about x.
+func rotX(theta float64) f64.Mat3 {
+	s, c := math.Sincos(theta)
+	return f64.Mat3{
+		1, 0, 0,
+		0, c, s,
+		0, -s, c,
+	}
+}
@@ -0,0 +1,106 @@
+package frames_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/frames"
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+func TestPerifocal(t *testing.T) {
+	a, e := 7000000.0, 0.1
+	w, lan, i := gravity.Radians(30), gravity.Radians(40), gravity.Radians(50)
+	m1, m2 := 5.972e24, float64(0)
+
+	t.Run("succeed in rotating periapsis onto the P axis", func(t *testing.T) {
+		r, v := gravity.StateVectors(a, e, w, lan, i, 0, 0, m1, m2)
+		p := frames.InertialToPerifocal(r, w, lan, i)
+		q := frames.InertialToPerifocal(v, w, lan, i)
+		require.Equal(t, fmt.Sprintf("%.3f", gravity.Periapsis(a, e)), fmt.Sprintf("%.3f", p[0]))
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", math.Abs(p[1])))
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", math.Abs(p[2])))
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", math.Abs(q[0])))
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", math.Abs(q[2])))
+	})
+	t.Run("succeed in rotating from perifocal back to inertial", func(t *testing.T) {
+		x := f64.Vec3{1, 2, 3}
+		y := frames.PerifocalToInertial(frames.InertialToPerifocal(x, w, lan, i), w, lan, i)
+		require.Equal(t, fmt.Sprintf("%.9f", x), fmt.Sprintf("%.9f", y))
+	})
+}
+
+func TestRTN(t *testing.T) {
+	r := f64.Vec3{7000000, 0, 0}
+	v := f64.Vec3{0, 5000, 5000}
+
+	t.Run("succeed in rotating position onto the R axis", func(t *testing.T) {
+		x := frames.InertialToRTN(r, r, v)
+		require.Equal(t, "[7000000.000 0.000 0.000]", fmt.Sprintf("%.3f", x))
+	})
+	t.Run("succeed in rotating velocity into the RT plane", func(t *testing.T) {
+		x := frames.InertialToRTN(v, r, v)
+		require.Equal(t, "[0.000 7071.068 0.000]", fmt.Sprintf("%.3f", x))
+	})
+	t.Run("succeed in rotating from RTN back to inertial", func(t *testing.T) {
+		x := f64.Vec3{1, 2, 3}
+		y := frames.RTNToInertial(frames.InertialToRTN(x, r, v), r, v)
+		require.Equal(t, fmt.Sprintf("%.9f", x), fmt.Sprintf("%.9f", y))
+	})
+	t.Run("succeed in removing frame rotation from relative velocity", func(t *testing.T) {
+		// a body trailing the reference on the same circular orbit is at
+		// rest in the rotating frame.
+		rc := f64.Vec3{7000000, 0, 0}
+		vc := f64.Vec3{0, 7000, 0}
+		dr := f64.Vec3{0, -1000, 0}
+		dv := f64.Vec3{1, 0, 0}
+		pr, pv := frames.InertialToRTNState(dr, dv, rc, vc)
+		require.Equal(t, "[0.000 -1000.000 0.000]", fmt.Sprintf("%.3f", pr))
+		require.Equal(t, "[0.000 0.000 0.000]", fmt.Sprintf("%.3f", pv))
+
+		ir, iv := frames.RTNToInertialState(pr, pv, rc, vc)
+		require.Equal(t, fmt.Sprintf("%.6f", dr), fmt.Sprintf("%.6f", ir))
+		require.Equal(t, fmt.Sprintf("%.6f", dv), fmt.Sprintf("%.6f", iv))
+	})
+}
+
+func TestBodyFixed(t *testing.T) {
+	rate := 7.2921159e-5
+
+	t.Run("succeed in removing surface velocity of a co-rotating point", func(t *testing.T) {
+		r := f64.Vec3{6378137, 0, 0}
+		v := f64.Vec3{0, 6378137 * rate, 0}
+		br, bv := frames.InertialToBodyFixed(r, v, rate, 0, 0)
+		require.Equal(t, "[6378137.000 0.000 0.000]", fmt.Sprintf("%.3f", br))
+		require.Equal(t, "[0.000 0.000 0.000]", fmt.Sprintf("%.3f", bv))
+	})
+	t.Run("succeed in rotating by the elapsed angle", func(t *testing.T) {
+		r := f64.Vec3{1000, 0, 0}
+		quarter := gravity.Pi / 2 / rate
+		br, _ := frames.InertialToBodyFixed(r, f64.Vec3{}, rate, quarter, 0)
+		require.Equal(t, "[0.000 -1000.000 0.000]", fmt.Sprintf("%.3f", br))
+	})
+	t.Run("succeed in transforming from body-fixed back to inertial", func(t *testing.T) {
+		r := f64.Vec3{1000, 2000, 3000}
+		v := f64.Vec3{4, 5, 6}
+		br, bv := frames.InertialToBodyFixed(r, v, rate, 1234, 0.5)
+		ir, iv := frames.BodyFixedToInertial(br, bv, rate, 1234, 0.5)
+		require.Equal(t, fmt.Sprintf("%.6f", r), fmt.Sprintf("%.6f", ir))
+		require.Equal(t, fmt.Sprintf("%.6f", v), fmt.Sprintf("%.6f", iv))
+	})
+}
+
+func TestEcliptic(t *testing.T) {
+	t.Run("succeed in rotating the ecliptic pole onto the equatorial frame", func(t *testing.T) {
+		x := frames.EclipticToEquatorial(f64.Vec3{0, 0, 1})
+		require.Equal(t, "[0.000000 -0.397777 0.917482]", fmt.Sprintf("%.6f", x))
+	})
+	t.Run("succeed in rotating from equatorial back to ecliptic", func(t *testing.T) {
+		x := f64.Vec3{1, 2, 3}
+		y := frames.EquatorialToEcliptic(frames.EclipticToEquatorial(x))
+		require.Equal(t, fmt.Sprintf("%.9f", x), fmt.Sprintf("%.9f", y))
+	})
+}
@@ -0,0 +1,104 @@
+package gravity
+
+import "math"
+
+// HyperbolicAnomaly (rad) using Newton's method.
+//
+// e: eccentricity        (>1),
+// m: hyperbolic mean anomaly (rad).
+//
+// https://en.wikipedia.org/wiki/Hyperbolic_trajectory#Hyperbolic_anomaly
+func HyperbolicAnomaly(e float64, m float64) float64 {
+	// Starting left of the root of the convex Kepler equation the iteration
+	// overshoots once and then converges monotonically.
+	h := math.Asinh(m / e)
+	for k := 0; k < 100; k++ {
+		d := (e*math.Sinh(h) - h - m) / (e*math.Cosh(h) - 1)
+		h -= d
+		if math.Abs(d) <= 1e-12*math.Max(1, math.Abs(h)) {
+			break
+		}
+	}
+	return h
+}
+
+// MeanAnomaly (rad) from true anomaly.
+//
+// e:  eccentricity (0-1 or >1),
+// nu: true anomaly (rad).
+//
+// For elliptical orbits whole revolutions of nu are kept so the result
+// increases with nu. For hyperbolic orbits the hyperbolic mean anomaly is
+// returned and nu must lie between the asymptotes.
+//
+// https://en.wikipedia.org/wiki/Mean_anomaly
+func MeanAnomaly(e, nu float64) float64 {
+	if e > 1 {
+		h := 2 * math.Atanh(math.Sqrt((e-1)/(e+1))*math.Tan(nu/2))
+		return e*math.Sinh(h) - h
+	}
+	revs := math.Floor(nu / (2 * math.Pi))
+	nu -= revs * 2 * math.Pi
+	eca := 2 * math.Atan2(math.Sqrt(1-e)*math.Sin(nu/2), math.Sqrt(1+e)*math.Cos(nu/2))
+	return eca - e*math.Sin(eca) + revs*2*math.Pi
+}
+
+// TrueAnomaly (rad) from mean anomaly.
+//
+// e: eccentricity (0-1 or >1),
+// m: mean anomaly, hyperbolic mean anomaly when e > 1 (rad).
+//
+// For elliptical orbits whole revolutions of m are kept.
+// See EccentricAnomaly and HyperbolicAnomaly for more details.
+func TrueAnomaly(e, m float64) float64 {
+	if e > 1 {
+		h := HyperbolicAnomaly(e, m)
+		return 2 * math.Atan(math.Sqrt((e+1)/(e-1))*math.Tanh(h/2))
+	}
+	revs := math.Floor(m / (2 * math.Pi))
+	eca := EccentricAnomaly(e, m-revs*2*math.Pi)
+	nu := 2 * math.Atan2(math.Sqrt(1+e)*math.Sin(eca/2), math.Sqrt(1-e)*math.Cos(eca/2))
+	if nu < 0 {
+		nu += 2 * math.Pi
+	}
+	return nu + revs*2*math.Pi
+}
+
+// TimeOfFlight (s) from true anomaly nu1 to nu2.
+//
+// a:   semi-major axis, negative for hyperbolic orbits (m),
+// e:   eccentricity                                    (0-1 or >1),
+// nu1: initial true anomaly                            (rad),
+// nu2: final true anomaly                              (rad),
+// mu:  standard gravitational parameter                (m^3/s^2).
+//
+// Elliptical orbits are flown forwards so the result is in [0, period).
+// Hyperbolic orbits are only flown once so the result is negative when
+// nu2 comes before nu1. Parabolic orbits are not supported.
+func TimeOfFlight(a, e, nu1, nu2, mu float64) float64 {
+	n := math.Sqrt(mu / math.Abs(a*a*a))
+	dm := MeanAnomaly(e, nu2) - MeanAnomaly(e, nu1)
+	if e < 1 {
+		dm -= math.Floor(dm/(2*math.Pi)) * 2 * math.Pi
+	}
+	return dm / n
+}
+
+// TrueAnomalyAfter t seconds (rad) starting from true anomaly nu0.
+//
+// a:   semi-major axis, negative for hyperbolic orbits (m),
+// e:   eccentricity                                    (0-1 or >1),
+// nu0: initial true anomaly                            (rad),
+// t:   time of flight, may be negative                 (s),
+// mu:  standard gravitational parameter                (m^3/s^2).
+//
+// The result is in [0, 2Pi) for elliptical orbits and between the
+// asymptotes for hyperbolic orbits. This is the inverse of TimeOfFlight.
+func TrueAnomalyAfter(a, e, nu0, t, mu float64) float64 {
+	n := math.Sqrt(mu / math.Abs(a*a*a))
+	m := MeanAnomaly(e, nu0) + n*t
+	if e < 1 {
+		m -= math.Floor(m/(2*math.Pi)) * 2 * math.Pi
+	}
+	return TrueAnomaly(e, m)
+}
@@ -0,0 +1,120 @@
+package gravity
+
+import (
+	"math"
+
+	"golang.org/x/image/math/f64"
+)
+
+// StateVectorsBatch at each time in t from one set of Keplerian Orbital
+// Elements.
+//
+// accepts:
+// a:   semi-major axis                  (m),
+// e:   eccentricity                     (0-1),
+// w:   argument of periapsis            (rad),
+// lan: longitude of ascending node      (rad),
+// i:   inclination                      (rad),
+// m0:  mean anomaly at epoch            (rad),
+// mu:  standard gravitational parameter (m^3/s^2),
+// t:   times since epoch                (seconds),
+// r:   destination for positions        (m),
+// v:   destination for velocities       (m/s).
+//
+// r and v must be at least as long as t. The rotation into the inertial
+// frame and the mean motion are computed once so each additional time only
+// costs the solution of Kepler's equation. No memory is allocated.
+//
+// See StateVectorsMu for more details.
+func StateVectorsBatch(a, e, w, lan, i, m0, mu float64, t []float64, r, v []f64.Vec3) {
+	o := newOrbit(a, e, w, lan, i, m0, mu)
+	r, v = r[:len(t)], v[:len(t)]
+	for k, tk := range t {
+		r[k], v[k] = o.at(tk)
+	}
+}
+
+// StateVectorsStep at len(r) evenly spaced times t0, t0+step, t0+2*step...
+// from one set of Keplerian Orbital Elements.
+//
+// accepts:
+// a:    semi-major axis                  (m),
+// e:    eccentricity                     (0-1),
+// w:    argument of periapsis            (rad),
+// lan:  longitude of ascending node      (rad),
+// i:    inclination                      (rad),
+// m0:   mean anomaly at epoch            (rad),
+// mu:   standard gravitational parameter (m^3/s^2),
+// t0:   first time since epoch           (seconds),
+// step: time between states              (seconds),
+// r:    destination for positions        (m),
+// v:    destination for velocities       (m/s).
+//
+// v must be at least as long as r. See StateVectorsBatch for more details.
+func StateVectorsStep(a, e, w, lan, i, m0, mu, t0, step float64, r, v []f64.Vec3) {
+	o := newOrbit(a, e, w, lan, i, m0, mu)
+	v = v[:len(r)]
+	for k := range r {
+		r[k], v[k] = o.at(t0 + float64(k)*step)
+	}
+}
+
+// orbit terms of StateVectorsMu which do not depend on time.
+type orbit struct {
+	a, e, m0, n       float64
+	sqrt1pe, sqrt1me  float64
+	sqrt1mee, sqrtmua float64
+	p, q              f64.Vec3 // perifocal x and y axes in the inertial frame
+}
+
+func newOrbit(a, e, w, lan, i, m0, mu float64) orbit {
+	sinw, cosw := math.Sin(w), math.Cos(w)
+	sinlan, coslan := math.Sin(lan), math.Cos(lan)
+	sini, cosi := math.Sin(i), math.Cos(i)
+	return orbit{
+		a:        a,
+		e:        e,
+		m0:       m0,
+		n:        math.Sqrt(mu / (a * a * a)),
+		sqrt1pe:  math.Sqrt(1 + e),
+		sqrt1me:  math.Sqrt(1 - e),
+		sqrt1mee: math.Sqrt(1 - (e * e)),
+		sqrtmua:  math.Sqrt(mu * a),
+		p: f64.Vec3{
+			cosw*coslan - sinw*cosi*sinlan,
+			cosw*sinlan + sinw*cosi*coslan,
+			sinw * sini,
+		},
+		q: f64.Vec3{
+			-(sinw*coslan + cosw*cosi*sinlan),
+			cosw*cosi*coslan - sinw*sinlan,
+			cosw * sini,
+		},
+	}
+}
+
+func (o orbit) at(t float64) (f64.Vec3, f64.Vec3) {
+	mT := o.m0 + t*o.n
+	ecaT := EccentricAnomaly(o.e, mT)
+	sinE2, cosE2 := math.Sincos(ecaT / 2)
+	taT := 2 * math.Atan2(o.sqrt1pe*sinE2, o.sqrt1me*cosE2)
+	sinE, cosE := math.Sincos(ecaT)
+	rcT := o.a * (1 - o.e*cosE)
+
+	sinta, costa := math.Sincos(taT)
+	x, y := rcT*costa, rcT*sinta
+	s := o.sqrtmua / rcT
+	vx, vy := -sinE*s, o.sqrt1mee*cosE*s
+
+	r := f64.Vec3{
+		x*o.p[0] + y*o.q[0],
+		x*o.p[1] + y*o.q[1],
+		x*o.p[2] + y*o.q[2],
+	}
+	v := f64.Vec3{
+		vx*o.p[0] + vy*o.q[0],
+		vx*o.p[1] + vy*o.q[1],
+		vx*o.p[2] + vy*o.q[2],
+	}
+	return r, v
+}
@@ -0,0 +1,70 @@
+package gravity
+
+import (
+	"math"
+
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+// Flyby of a planet modeled as an instantaneous rotation of the hyperbolic
+// excess velocity.
+//
+// accepts:
+// vin:   incoming hyperbolic excess velocity relative to the planet (m/s),
+// rp:    periapsis radius of the flyby                             (m),
+// theta: B-plane angle                                             (rad),
+// mu:    standard gravitational parameter of the planet            (m^3/s^2).
+//
+// returns:
+// vout:  outgoing hyperbolic excess velocity relative to the planet (m/s),
+// delta: turning angle                                            (rad),
+// dv:    change in heliocentric velocity                           (m/s).
+//
+// The B-plane has T along vin x z, falling back to the x axis when vin is
+// parallel to z, and R along vin x T so that theta is measured from T
+// towards R. The B vector points from the planet to where the incoming
+// asymptote crosses the B-plane at angle theta, and the trajectory bends
+// the opposite way around the planet. The velocity of the planet does not
+// change during the flyby so the heliocentric velocity changes by
+// vout - vin.
+//
+// https://en.wikipedia.org/wiki/Gravity_assist
+func Flyby(vin f64.Vec3, rp, theta, mu float64) (vout f64.Vec3, delta float64, dv f64.Vec3) {
+	vinf := vec3.Magnitude(vin)
+	delta = TurnAngle(vinf, rp, mu)
+	s := vec3.DivScalar(vin, vinf)
+	t := vec3.Cross(s, f64.Vec3{0, 0, 1})
+	if vec3.Magnitude(t) < Epsilon6 {
+		t = vec3.Cross(s, f64.Vec3{1, 0, 0})
+	}
+	t = vec3.Normalize(t)
+	r := vec3.Cross(s, t)
+	b := vec3.Add(vec3.MulScalar(t, math.Cos(theta)), vec3.MulScalar(r, math.Sin(theta)))
+	vout = vec3.MulScalar(vec3.Sub(vec3.MulScalar(s, math.Cos(delta)), vec3.MulScalar(b, math.Sin(delta))), vinf)
+	return vout, delta, vec3.Sub(vout, vin)
+}
+
+// TurnAngle (rad) of the hyperbolic excess velocity during a flyby.
+//
+// vinf: hyperbolic excess speed                    (m/s),
+// rp:   periapsis radius of the flyby              (m),
+// mu:   standard gravitational parameter of planet (m^3/s^2).
+//
+// https://en.wikipedia.org/wiki/Hyperbolic_trajectory#Deflection_angle
+func TurnAngle(vinf, rp, mu float64) float64 {
+	return 2 * math.Asin(1/(1+rp*vinf*vinf/mu))
+}
+
+// FlybyPeriapsis radius (m) which turns the hyperbolic excess velocity by
+// delta. This is the inverse of TurnAngle.
+//
+// vinf:  hyperbolic excess speed                    (m/s),
+// delta: turning angle in (0, Pi)                   (rad),
+// mu:    standard gravitational parameter of planet (m^3/s^2).
+//
+// Compare the result with the radius of the planet plus a safe altitude
+// since large turns may need a periapsis below the surface.
+func FlybyPeriapsis(vinf, delta, mu float64) float64 {
+	return mu / (vinf * vinf) * (1/math.Sin(delta/2) - 1)
+}
@@ -14,6 +14,70 @@ const (
 	Epsilon6 float64 = 1e-06 // small number
 )
 
+const (
+	G2018     float64 = 6.67430e-11  // CODATA 2018 gravitational constant     (m^3/(kg*s^2))
+	GMSun     float64 = 1.3271244e20 // IAU 2015 nominal solar mass parameter    (m^3/s^2)
+	GMEarth   float64 = 3.986004e14  // IAU 2015 nominal earth mass parameter    (m^3/s^2)
+	GMJupiter float64 = 1.2668653e17 // IAU 2015 nominal jupiter mass parameter  (m^3/s^2)
+	AU        float64 = 149597870700 // IAU 2012 astronomical unit               (m)
+)
+
+// Constants set used by the mass based functions.
+//
+// G: gravitational constant (m^3/(kg*s^2)).
+//
+// The package level mass based functions use Legacy. Prefer the Mu
+// variants such as OrbitalElementsMu with a published standard
+// gravitational parameter (see the bodies package) when precision
+// matters since GM is known far more precisely than G or mass alone.
+//
+// https://physics.nist.gov/cgi-bin/cuu/Value?bg
+type Constants struct {
+	G float64
+}
+
+var (
+	Legacy     = Constants{G: G}
+	CODATA2018 = Constants{G: G2018}
+)
+
+// Mu standard gravitational parameter (m^3/s^2).
+//
+// m1: mass of the primary body   (kg),
+// m2: mass of the secondary body (kg).
+//
+// If the primary body is on-rails then set m2 to 0.
+// See OrbitalElements for more details.
+//
+// https://en.wikipedia.org/wiki/Standard_gravitational_parameter
+func (c Constants) Mu(m1, m2 float64) float64 {
+	return c.G * (m1 + m2)
+}
+
+// Force using the constants set. See Force for more details.
+func (c Constants) Force(p1 f64.Vec3, p2 f64.Vec3, m1 float64, m2 float64) f64.Vec3 {
+	r := vec3.Sub(p2, p1)
+	d := vec3.Magnitude(r)
+	rhat := vec3.DivScalar(r, d)
+	return vec3.MulScalar(rhat, -(c.G*m1*m2)/(d*d))
+}
+
+// OrbitalElements using the constants set. See OrbitalElements for more
+// details.
+func (c Constants) OrbitalElements(r f64.Vec3, v f64.Vec3, m1 float64, m2 float64) (a, e, w, lan, i, m float64) {
+	return OrbitalElementsMu(r, v, c.Mu(m1, m2))
+}
+
+// StateVectors using the constants set. See StateVectors for more details.
+func (c Constants) StateVectors(a, e, w, lan, i, m0, t, m1, m2 float64) (f64.Vec3, f64.Vec3) {
+	return StateVectorsMu(a, e, w, lan, i, m0, t, c.Mu(m1, m2))
+}
+
+// Period using the constants set. See Period for more details.
+func (c Constants) Period(a, m1, m2 float64) float64 {
+	return PeriodMu(a, c.Mu(m1, m2))
+}
+
 // Force vector due to gravity (N) using Netwon's law of universal gravitation.
 //
 // p1: position of the primary body   (m),
@@ -26,10 +90,7 @@ const (
 //
 // https://en.wikipedia.org/wiki/Newton%27s_law_of_universal_gravitation#Vector_form
 func Force(p1 f64.Vec3, p2 f64.Vec3, m1 float64, m2 float64) f64.Vec3 {
-	r := vec3.Sub(p2, p1)
-	d := vec3.Magnitude(r)
-	rhat := vec3.DivScalar(r, d)
-	return vec3.MulScalar(rhat, -(G*m1*m2)/(d*d))
+	return Legacy.Force(p1, p2, m1, m2)
 }
 
 // EccentricAnomaly (rad) using Newton's method.
@@ -37,8 +98,15 @@ func Force(p1 f64.Vec3, p2 f64.Vec3, m1 float64, m2 float64) f64.Vec3 {
 // e: eccentricity (0-1),
 // m: mean anomaly (rad).
 //
+// The mean anomaly is reduced to [0, 2Pi) before iterating since the
+// initial guess for high eccentricities does not converge for mean
+// anomalies many revolutions away. The removed revolutions are added back
+// to the result.
+//
 // http://www.csun.edu/~hcmth017/master/node16.html
 func EccentricAnomaly(e float64, m float64) float64 {
+	revs := math.Floor(m / (2 * math.Pi))
+	m -= revs * 2 * math.Pi
 	eca := m + e/2
 	if e >= 0.60 {
 		eca = Pi
@@ -50,7 +118,7 @@ func EccentricAnomaly(e float64, m float64) float64 {
 		diff = math.Abs(e1 - eca)
 		eca = e1
 	}
-	return eca
+	return eca + revs*2*math.Pi
 }
 
 // OrbitalElements from Cartesian State Vectors.
@@ -88,6 +156,30 @@ func EccentricAnomaly(e float64, m float64) float64 {
 //
 // https://downloads.rene-schwarz.com/download/M002-Cartesian_State_Vectors_to_Keplerian_Orbit_Elements.pdf
 func OrbitalElements(r f64.Vec3, v f64.Vec3, m1 float64, m2 float64) (a, e, w, lan, i, m float64) {
+	return Legacy.OrbitalElements(r, v, m1, m2)
+}
+
+// OrbitalElementsMu from Cartesian State Vectors.
+//
+// accepts:
+// r:  position relative to primary body (m),
+// v:  velocity relative to primary body (m/s),
+// mu: standard gravitational parameter  (m^3/s^2).
+//
+// returns:
+// a:   semi-major axis                  (m),
+// e:   eccentricity                     (0-1 or >1),
+// w:   argument of periapsis            (rad),
+// lan: longitude of ascending node      (rad),
+// i:   inclination                      (rad),
+// m:   mean anomaly                     (rad).
+//
+// For hyperbolic orbits a is negative and m is the hyperbolic mean
+// anomaly, negative before periapsis, as used by MeanAnomaly and
+// TrueAnomaly. Parabolic orbits (e = 1) are not supported.
+//
+// See OrbitalElements for more details.
+func OrbitalElementsMu(r f64.Vec3, v f64.Vec3, mu float64) (a, e, w, lan, i, m float64) {
 	if r[2] == 0 {
 		r[2] = Epsilon
 	}
@@ -96,8 +188,6 @@ func OrbitalElements(r f64.Vec3, v f64.Vec3, m1 float64, m2 float64) (a, e, w, l
 		v[2] = Epsilon
 	}
 
-	mu := G * (m1 + m2)
-
 	rmag := vec3.Magnitude(r)
 	vmag := vec3.Magnitude(v)
 
@@ -113,31 +203,36 @@ func OrbitalElements(r f64.Vec3, v f64.Vec3, m1 float64, m2 float64) (a, e, w, l
 	n := f64.Vec3{-h[1], h[0], 0}
 	nmag := vec3.Magnitude(n)
 
-	ta := math.Acos(vec3.Dot(evec, r) / (e * rmag))
-	if vec3.Dot(r, v) < 0 {
+	// r.v can round to just below zero at periapsis so only wrap a non-zero
+	// ta, otherwise 2Pi gives a mean anomaly of ~-4e-15 instead of 0.
+	ta := acos(vec3.Dot(evec, r) / (e * rmag))
+	if vec3.Dot(r, v) < 0 && ta != 0 {
 		ta = 2*Pi - ta
 	}
 
-	i = math.Acos(h[2] / vec3.Magnitude(h))
+	i = acos(h[2] / vec3.Magnitude(h))
 	if i == 0 {
 		i = Epsilon
 	} else if i == Pi {
 		i -= Epsilon
 	}
 
-	eca := 2 * math.Atan(math.Tan(ta/2)/math.Sqrt((1+e)/(1-e)))
-
-	lan = math.Acos(n[0] / nmag)
+	lan = acos(n[0] / nmag)
 	if n[1] < 0 {
 		lan = 2*Pi - lan
 	}
 
-	w = math.Acos(vec3.Dot(n, evec) / (nmag * e))
+	w = acos(vec3.Dot(n, evec) / (nmag * e))
 	if evec[2] < 0 {
 		w = 2*Pi - w
 	}
 
-	m = eca - (e * math.Sin(eca))
+	if e > 1 {
+		m = MeanAnomaly(e, ta)
+	} else {
+		eca := 2 * math.Atan(math.Tan(ta/2)/math.Sqrt((1+e)/(1-e)))
+		m = eca - (e * math.Sin(eca))
+	}
 
 	a = 1 / ((2 / rmag) - ((vmag * vmag) / mu))
 
@@ -166,12 +261,28 @@ func OrbitalElements(r f64.Vec3, v f64.Vec3, m1 float64, m2 float64) (a, e, w, l
 //
 // https://downloads.rene-schwarz.com/download/M001-Keplerian_Orbit_Elements_to_Cartesian_State_Vectors.pdf
 func StateVectors(a, e, w, lan, i, m0, t, m1, m2 float64) (f64.Vec3, f64.Vec3) {
-	mu := G * (m1 + m2)
+	return Legacy.StateVectors(a, e, w, lan, i, m0, t, m1, m2)
+}
 
-	mT := float64(0)
-	if t != 0 {
-		mT = m0 + (t * math.Sqrt(mu/(a*a*a)))
-	}
+// StateVectorsMu at t seconds after epoch from Keplerian Orbital Elements.
+//
+// accepts:
+// a:   semi-major axis                  (m),
+// e:   eccentricity                     (0-1),
+// w:   argument of periapsis            (rad),
+// lan: longitude of ascending node      (rad),
+// i:   inclination                      (rad),
+// m0:  mean anomaly at epoch            (rad),
+// t:   time since epoch                 (seconds),
+// mu:  standard gravitational parameter (m^3/s^2).
+//
+// returns:
+// r:  position relative to primary body (m),
+// v:  velocity relative to primary body (m/s).
+//
+// See StateVectors for more details.
+func StateVectorsMu(a, e, w, lan, i, m0, t, mu float64) (f64.Vec3, f64.Vec3) {
+	mT := m0 + t*math.Sqrt(mu/(a*a*a))
 	ecaT := EccentricAnomaly(e, mT)
 	taT := 2 * (math.Atan2(math.Sqrt(1+e)*math.Sin(ecaT/2), math.Sqrt(1-e)*math.Cos(ecaT/2)))
 	rcT := a * (1 - e*math.Cos(ecaT))
@@ -236,7 +347,16 @@ func Apoapsis(a, e float64) float64 {
 //
 // https://en.wikipedia.org/wiki/Orbital_period
 func Period(a, m1, m2 float64) float64 {
-	mu := G * (m1 + m2)
+	return Legacy.Period(a, m1, m2)
+}
+
+// PeriodMu (s).
+//
+// a:  semi-major axis                  (m),
+// mu: standard gravitational parameter (m^3/s^2).
+//
+// https://en.wikipedia.org/wiki/Orbital_period
+func PeriodMu(a, mu float64) float64 {
 	return (2 * Pi) * math.Sqrt((a*a*a)/mu)
 }
 
@@ -249,3 +369,9 @@ func Degrees(rad float64) float64 {
 func Radians(deg float64) float64 {
 	return Pi * deg / 180
 }
+
+// acos is math.Acos with x clamped to [-1, 1] so that rounding errors near
+// the apsides and nodes do not produce NaN.
+func acos(x float64) float64 {
+	return math.Acos(math.Max(-1, math.Min(1, x)))
+}
@@ -74,6 +74,22 @@ func BenchmarkStateVectors(b *testing.B) {
 	}
 }
 
+func BenchmarkStateVectorsMu(b *testing.B) {
+	rand.Seed(time.Now().UnixNano())
+	for i := 0; i < b.N; i++ {
+		gravity.StateVectorsMu(
+			rand.NormFloat64(),
+			rand.Float64(),
+			gravity.Radians(float64(rand.Intn(360))),
+			gravity.Radians(float64(rand.Intn(360))),
+			gravity.Radians(float64(rand.Intn(360))),
+			gravity.Radians(float64(rand.Intn(360))),
+			math.Abs(rand.NormFloat64()),
+			math.Abs(rand.NormFloat64()),
+		)
+	}
+}
+
 func BenchmarkPeriapsis(b *testing.B) {
 	rand.Seed(time.Now().UnixNano())
 	for i := 0; i < b.N; i++ {
@@ -108,3 +124,55 @@ func BenchmarkRadians(b *testing.B) {
 		gravity.Radians(rand.NormFloat64())
 	}
 }
+
+func BenchmarkStateVectorsStep(b *testing.B) {
+	r := make([]f64.Vec3, 1000)
+	v := make([]f64.Vec3, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gravity.StateVectorsStep(7000e3, 0.2, 1.1, 2.2, 0.9, 0.4, gravity.GMEarth, 0, 60, r, v)
+	}
+}
+
+func newBenchmarkBatch(n int) *gravity.Batch {
+	a, e, w, lan, i, m0, mu := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	for k := 0; k < n; k++ {
+		a[k] = 6800e3 + rand.Float64()*30000e3
+		e[k] = rand.Float64() * 0.7
+		w[k] = gravity.Radians(float64(rand.Intn(360)))
+		lan[k] = gravity.Radians(float64(rand.Intn(360)))
+		i[k] = gravity.Radians(float64(rand.Intn(180)))
+		m0[k] = gravity.Radians(float64(rand.Intn(360)))
+		mu[k] = gravity.GMEarth
+	}
+	return gravity.NewBatch(a, e, w, lan, i, m0, mu)
+}
+
+func BenchmarkBatchStateVectors(b *testing.B) {
+	rand.Seed(time.Now().UnixNano())
+	batch := newBenchmarkBatch(100000)
+	r := make([]f64.Vec3, batch.Len())
+	v := make([]f64.Vec3, batch.Len())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch.StateVectors(float64(i), r, v)
+	}
+}
+
+func BenchmarkBatchStateVectorsParallel(b *testing.B) {
+	rand.Seed(time.Now().UnixNano())
+	batch := newBenchmarkBatch(100000)
+	r := make([]f64.Vec3, batch.Len())
+	v := make([]f64.Vec3, batch.Len())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch.StateVectorsParallel(float64(i), r, v, 0)
+	}
+}
+
+func BenchmarkPropagate(b *testing.B) {
+	r, v := f64.Vec3{7000e3, 0, 0}, f64.Vec3{0, 12e3, 1e3}
+	for i := 0; i < b.N; i++ {
+		gravity.Propagate(r, v, 3600, gravity.GMEarth)
+	}
+}
@@ -2,9 +2,11 @@ package gravity_test
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
 	"github.com/wafer-bw/gorbit/gravity"
 	"github.com/wafer-bw/gorbit/vec3"
 	"golang.org/x/image/math/f64"
@@ -118,6 +120,33 @@ func TestOrbitalElements(t *testing.T) {
 		require.Equal(t, fmt.Sprintf("%.3f", expectedPE), fmt.Sprintf("%.3f", gravity.Periapsis(a, e)))
 		require.Equal(t, fmt.Sprintf("%.3f", expectedAP), fmt.Sprintf("%.3f", gravity.Apoapsis(a, e)))
 	})
+	t.Run("succeed in calculating orbital elements exactly at periapsis", func(t *testing.T) {
+		m1, m2 := 5.972e24, 7.34767309e22
+
+		// the true anomaly cosine rounds to just above 1 for this state.
+		r, v := gravity.StateVectors(5e8, 0.2, 0, 1.57, 3.13, 0, 0, m1, m2)
+
+		a, e, w, lan, i, m := gravity.OrbitalElements(r, v, m1, m2)
+		require.Equal(t, "500000000.000", fmt.Sprintf("%.3f", a))
+		require.Equal(t, "0.200", fmt.Sprintf("%.3f", e))
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", math.Abs(math.Remainder(w, 2*math.Pi))))
+		require.Equal(t, "1.570", fmt.Sprintf("%.3f", lan))
+		require.Equal(t, "3.130", fmt.Sprintf("%.3f", i))
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", m))
+	})
+	t.Run("succeed in calculating orbital elements for a hyperbolic orbit", func(t *testing.T) {
+		// escape from earth ten minutes before and after periapsis.
+		rp, vp := f64.Vec3{7000e3, 0, 0}, f64.Vec3{0, 12e3, 0}
+		for _, dt := range []float64{-600, 600} {
+			r, v := gravity.Propagate(rp, vp, dt, gravity.GMEarth)
+			a, e, _, _, _, m := gravity.OrbitalElementsMu(r, v, gravity.GMEarth)
+			require.Less(t, a, 0.0)
+			require.Greater(t, e, 1.0)
+			require.Equal(t, "7000000.000", fmt.Sprintf("%.3f", a*(1-e)))
+			n := math.Sqrt(gravity.GMEarth / -(a * a * a))
+			require.Equal(t, fmt.Sprintf("%.3f", dt), fmt.Sprintf("%.3f", m/n))
+		}
+	})
 }
 
 func TestEccentricAnomaly(t *testing.T) {
@@ -173,6 +202,28 @@ func TestEccentricAnomaly(t *testing.T) {
 			eca := gravity.EccentricAnomaly(0.976337273503912, gravity.Radians(333.00000000000006))
 			require.Equal(t, "4.8440605", fmt.Sprintf("%.7f", eca))
 		})
+		require.NotPanics(t, func() {
+			eca := gravity.EccentricAnomaly(0.64, 292.3979709297559)
+			m := eca - 0.64*math.Sin(eca)
+			require.Equal(t, "292.3979709", fmt.Sprintf("%.7f", m))
+		})
+	})
+
+	t.Run("succeed for mean anomalies many revolutions from epoch", func(t *testing.T) {
+		// without reducing the mean anomaly the first four cycle between the
+		// same few Newton iterates forever.
+		for _, tc := range []struct{ e, m float64 }{
+			{0.7, 123.58},
+			{0.7, 462.87},
+			{0.7, -2957.89},
+			{0.7, -2982.42},
+			{0.9, 1e6},
+			{0.99, -123456.78},
+		} {
+			eca := gravity.EccentricAnomaly(tc.e, tc.m)
+			require.Equal(t, fmt.Sprintf("%.7f", tc.m), fmt.Sprintf("%.7f", eca-tc.e*math.Sin(eca)))
+			require.Equal(t, math.Floor(tc.m/(2*math.Pi)), math.Floor(eca/(2*math.Pi)))
+		}
 	})
 }
 
@@ -247,6 +298,45 @@ func TestStateVectors(t *testing.T) {
 		require.Equal(t, fmt.Sprintf("%.6f", expectedVY), fmt.Sprintf("%.6f", v[1]))
 		require.Equal(t, fmt.Sprintf("-%.6f", expectedVZ), fmt.Sprintf("%.6f", v[2]))
 	})
+	t.Run("succeed in using the mean anomaly at epoch (t0)", func(t *testing.T) {
+		a := 502989447.71483934
+		e := 0.1940188768535872
+		w := 0.0000011540590637606703
+		lan := 90.00015405130662
+		i := 180.00000000000017
+		m1, m2 := 5.972e24, 7.34767309e22
+
+		expectedR, expectedV := gravity.StateVectors(
+			a,
+			e,
+			gravity.Radians(w),
+			gravity.Radians(lan),
+			gravity.Radians(i),
+			0,
+			gravity.Period(a, m1, m2)/2,
+			m1,
+			m2,
+		)
+
+		r, v := gravity.StateVectors(
+			a,
+			e,
+			gravity.Radians(w),
+			gravity.Radians(lan),
+			gravity.Radians(i),
+			gravity.Radians(180),
+			0,
+			m1,
+			m2,
+		)
+		require.Equal(t, fmt.Sprintf("%.3f", expectedR[0]), fmt.Sprintf("%.3f", r[0]))
+		require.Equal(t, fmt.Sprintf("%.3f", expectedR[1]), fmt.Sprintf("%.3f", r[1]))
+		require.Equal(t, fmt.Sprintf("%.3f", expectedR[2]), fmt.Sprintf("%.3f", r[2]))
+		require.Equal(t, fmt.Sprintf("%.6f", expectedV[0]), fmt.Sprintf("%.6f", v[0]))
+		require.Equal(t, fmt.Sprintf("%.6f", expectedV[1]), fmt.Sprintf("%.6f", v[1]))
+		require.Equal(t, fmt.Sprintf("%.6f", expectedV[2]), fmt.Sprintf("%.6f", v[2]))
+		require.Equal(t, fmt.Sprintf("%.3f", gravity.Apoapsis(a, e)), fmt.Sprintf("%.3f", vec3.Magnitude(r)))
+	})
 }
 
 func TestSvToOeToSv(t *testing.T) {
@@ -273,3 +363,435 @@ func TestSvToOeToSv(t *testing.T) {
 	require.Equal(t, fmt.Sprintf("%.6f", R[1]), fmt.Sprintf("%.6f", R2[1]))
 	require.Equal(t, fmt.Sprintf("%.6f", R[2]), fmt.Sprintf("%.6f", R2[2]))
 }
+
+func TestMu(t *testing.T) {
+	t.Run("succeed in calculating the period of a geostationary orbit", func(t *testing.T) {
+		p := gravity.PeriodMu(42164137, bodies.Earth.GM)
+		require.Equal(t, "86164", fmt.Sprintf("%.0f", p))
+	})
+	t.Run("succeed in matching published GM using CODATA 2018 G", func(t *testing.T) {
+		mu := gravity.CODATA2018.Mu(bodies.Earth.Mass, 0)
+		require.Equal(t, fmt.Sprintf("%.4e", bodies.Earth.GM), fmt.Sprintf("%.4e", mu))
+	})
+	t.Run("succeed in matching mass based functions using legacy constants", func(t *testing.T) {
+		m1, m2 := 5.972e24, 7.34767309e22
+		r := f64.Vec3{0, 405400000, 100}
+		v := f64.Vec3{1090, 0, 10}
+		a, e, w, lan, i, m := gravity.OrbitalElements(r, v, m1, m2)
+		a2, e2, w2, lan2, i2, m2mu := gravity.OrbitalElementsMu(r, v, gravity.Legacy.Mu(m1, m2))
+		require.Equal(t, []float64{a, e, w, lan, i, m}, []float64{a2, e2, w2, lan2, i2, m2mu})
+		require.Equal(t, gravity.Period(a, m1, m2), gravity.PeriodMu(a, gravity.Legacy.Mu(m1, m2)))
+		require.Equal(t, gravity.Force(r, f64.Vec3{}, m1, m2), gravity.Legacy.Force(r, f64.Vec3{}, m1, m2))
+	})
+	t.Run("succeed in calculating state vectors at epoch from mean anomaly", func(t *testing.T) {
+		a, e := 7000000.0, 0.1
+		r, v := gravity.StateVectorsMu(a, e, 0, 0, 0, gravity.Pi, 0, bodies.Earth.GM)
+		require.Equal(t, fmt.Sprintf("%.3f", gravity.Apoapsis(a, e)), fmt.Sprintf("%.3f", vec3.Magnitude(r)))
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", math.Abs(vec3.Dot(r, v))/vec3.Magnitude(r)))
+	})
+}
+
+func TestStateVectorsBatch(t *testing.T) {
+	a, e, w, lan, i, m0 := 7000e3, 0.2, 1.1, 2.2, 0.9, 0.4
+	mu := bodies.Earth.GM
+	times := []float64{0, 60, 600, 3600, -1800, 86400}
+
+	t.Run("succeed in matching StateVectorsMu", func(t *testing.T) {
+		r := make([]f64.Vec3, len(times))
+		v := make([]f64.Vec3, len(times))
+		gravity.StateVectorsBatch(a, e, w, lan, i, m0, mu, times, r, v)
+		for k, tk := range times {
+			rk, vk := gravity.StateVectorsMu(a, e, w, lan, i, m0, tk, mu)
+			require.Less(t, vec3.Magnitude(vec3.Sub(rk, r[k])), 1e-6)
+			require.Less(t, vec3.Magnitude(vec3.Sub(vk, v[k])), 1e-9)
+		}
+	})
+	t.Run("succeed in matching StateVectorsBatch with evenly spaced times", func(t *testing.T) {
+		r1 := make([]f64.Vec3, 10)
+		v1 := make([]f64.Vec3, 10)
+		gravity.StateVectorsStep(a, e, w, lan, i, m0, mu, 100, 30, r1, v1)
+		steps := make([]float64, 10)
+		for k := range steps {
+			steps[k] = 100 + float64(k)*30
+		}
+		r2 := make([]f64.Vec3, 10)
+		v2 := make([]f64.Vec3, 10)
+		gravity.StateVectorsBatch(a, e, w, lan, i, m0, mu, steps, r2, v2)
+		require.Equal(t, r2, r1)
+		require.Equal(t, v2, v1)
+	})
+	t.Run("succeed without allocating", func(t *testing.T) {
+		r := make([]f64.Vec3, len(times))
+		v := make([]f64.Vec3, len(times))
+		allocs := testing.AllocsPerRun(10, func() {
+			gravity.StateVectorsBatch(a, e, w, lan, i, m0, mu, times, r, v)
+		})
+		require.Equal(t, float64(0), allocs)
+	})
+}
+
+func TestBatch(t *testing.T) {
+	n := 1000
+	a, e, w, lan, i, m0, mu := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	for k := 0; k < n; k++ {
+		a[k] = 7000e3 + float64(k)*1e3
+		e[k] = float64(k%90) / 100
+		w[k], lan[k], i[k], m0[k] = float64(k)*0.1, float64(k)*0.2, float64(k%180)*math.Pi/180, float64(k)*0.3
+		mu[k] = bodies.Earth.GM
+	}
+	b := gravity.NewBatch(a, e, w, lan, i, m0, mu)
+	require.Equal(t, n, b.Len())
+
+	t.Run("succeed in matching StateVectorsMu", func(t *testing.T) {
+		r := make([]f64.Vec3, n)
+		v := make([]f64.Vec3, n)
+		b.StateVectors(3600, r, v)
+		for k := 0; k < n; k++ {
+			rk, vk := gravity.StateVectorsMu(a[k], e[k], w[k], lan[k], i[k], m0[k], 3600, mu[k])
+			require.Less(t, vec3.Magnitude(vec3.Sub(rk, r[k])), 1e-6)
+			require.Less(t, vec3.Magnitude(vec3.Sub(vk, v[k])), 1e-9)
+		}
+	})
+	t.Run("succeed in matching StateVectors across goroutines", func(t *testing.T) {
+		r1, v1 := make([]f64.Vec3, n), make([]f64.Vec3, n)
+		b.StateVectors(600, r1, v1)
+		for _, workers := range []int{0, 1, 3, 7, 2000} {
+			r2, v2 := make([]f64.Vec3, n), make([]f64.Vec3, n)
+			b.StateVectorsParallel(600, r2, v2, workers)
+			require.Equal(t, r1, r2)
+			require.Equal(t, v1, v2)
+		}
+	})
+	t.Run("fail when the element slices differ in length", func(t *testing.T) {
+		require.Panics(t, func() { gravity.NewBatch(a, e, w, lan, i, m0, mu[1:]) })
+	})
+}
+
+func TestHohmann(t *testing.T) {
+	t.Run("succeed in calculating a LEO to GEO transfer", func(t *testing.T) {
+		dv1, dv2, tof := gravity.Hohmann(6678e3, 42164e3, bodies.Earth.GM)
+		require.Equal(t, "2.426", fmt.Sprintf("%.3f", dv1/1000))
+		require.Equal(t, "1.467", fmt.Sprintf("%.3f", dv2/1000))
+		require.Equal(t, "5.28", fmt.Sprintf("%.2f", tof/3600))
+	})
+	t.Run("succeed in calculating a transfer to a lower orbit", func(t *testing.T) {
+		up1, up2, upTof := gravity.Hohmann(6678e3, 42164e3, bodies.Earth.GM)
+		down1, down2, downTof := gravity.Hohmann(42164e3, 6678e3, bodies.Earth.GM)
+		require.Equal(t, fmt.Sprintf("%.6f", up1), fmt.Sprintf("%.6f", down2))
+		require.Equal(t, fmt.Sprintf("%.6f", up2), fmt.Sprintf("%.6f", down1))
+		require.Equal(t, upTof, downTof)
+	})
+}
+
+func TestSphereOfInfluence(t *testing.T) {
+	t.Run("succeed in matching the published sphere of influence of earth", func(t *testing.T) {
+		soi := gravity.LaplaceSOI(gravity.AU, bodies.Sun.GM, bodies.Earth.GM)
+		require.Equal(t, "9.25e+08", fmt.Sprintf("%.2e", soi))
+	})
+	t.Run("succeed in calculating the hill sphere of earth", func(t *testing.T) {
+		r := gravity.HillSphere(gravity.AU, 0, bodies.Sun.GM, bodies.Earth.GM)
+		require.Equal(t, "1.50e+09", fmt.Sprintf("%.2e", r))
+	})
+	t.Run("succeed in shrinking the hill sphere with eccentricity", func(t *testing.T) {
+		require.Less(t,
+			gravity.HillSphere(gravity.AU, 0.1, bodies.Sun.GM, bodies.Earth.GM),
+			gravity.HillSphere(gravity.AU, 0, bodies.Sun.GM, bodies.Earth.GM),
+		)
+	})
+}
+
+func TestPropagate(t *testing.T) {
+	mu := bodies.Earth.GM
+	t.Run("succeed in matching StateVectorsMu for an ellipse", func(t *testing.T) {
+		a, e, w, lan, i, m0 := 9000e3, 0.3, 0.4, 1.2, 0.7, 0.1
+		r0, v0 := gravity.StateVectorsMu(a, e, w, lan, i, m0, 0, mu)
+		for _, dt := range []float64{1, 600, 5000, -3000, 250000} {
+			wantR, wantV := gravity.StateVectorsMu(a, e, w, lan, i, m0, dt, mu)
+			r, v := gravity.Propagate(r0, v0, dt, mu)
+			require.Less(t, vec3.Magnitude(vec3.Sub(r, wantR)), 1e-3, dt)
+			require.Less(t, vec3.Magnitude(vec3.Sub(v, wantV)), 1e-6, dt)
+		}
+	})
+	t.Run("succeed in conserving energy and momentum on a hyperbola", func(t *testing.T) {
+		r0, v0 := f64.Vec3{7000e3, 0, 0}, f64.Vec3{0, 12e3, 1e3}
+		energy := func(r, v f64.Vec3) float64 {
+			return vec3.Dot(v, v)/2 - mu/vec3.Magnitude(r)
+		}
+		for _, dt := range []float64{60, 3600, 86400 * 10} {
+			r, v := gravity.Propagate(r0, v0, dt, mu)
+			require.Equal(t, fmt.Sprintf("%.6e", energy(r0, v0)), fmt.Sprintf("%.6e", energy(r, v)), dt)
+			h0, h := vec3.Cross(r0, v0), vec3.Cross(r, v)
+			require.Less(t, vec3.Magnitude(vec3.Sub(h, h0))/vec3.Magnitude(h0), 1e-9, dt)
+			back, _ := gravity.Propagate(r, v, -dt, mu)
+			require.Less(t, vec3.Magnitude(vec3.Sub(back, r0)), 1e-2, dt)
+		}
+	})
+	t.Run("succeed in propagating a parabola", func(t *testing.T) {
+		r0 := f64.Vec3{7000e3, 0, 0}
+		v0 := f64.Vec3{0, math.Sqrt(2 * mu / 7000e3), 0}
+		r, v := gravity.Propagate(r0, v0, 3600, mu)
+		require.Equal(t, "0.000", fmt.Sprintf("%.3f", math.Abs(vec3.Dot(v, v)/2-mu/vec3.Magnitude(r))))
+	})
+	t.Run("succeed in returning the input for no time", func(t *testing.T) {
+		r, v := gravity.Propagate(f64.Vec3{1, 2, 3}, f64.Vec3{4, 5, 6}, 0, mu)
+		require.Equal(t, f64.Vec3{1, 2, 3}, r)
+		require.Equal(t, f64.Vec3{4, 5, 6}, v)
+	})
+}
+
+func TestAnomalies(t *testing.T) {
+	t.Run("succeed in converting between mean and true anomaly", func(t *testing.T) {
+		for _, e := range []float64{0.001, 0.3, 0.9, 1.5, 4} {
+			for _, nu := range []float64{-1.5, -0.2, 0, 0.7, 1.7} {
+				got := gravity.TrueAnomaly(e, gravity.MeanAnomaly(e, nu))
+				require.Equal(t, "0.000000000", fmt.Sprintf("%.9f", math.Abs(math.Remainder(got-nu, 2*math.Pi))), e)
+			}
+		}
+	})
+	t.Run("succeed in keeping whole revolutions of elliptical orbits", func(t *testing.T) {
+		m := gravity.MeanAnomaly(0.4, 1)
+		require.Equal(t, fmt.Sprintf("%.9f", m+4*math.Pi), fmt.Sprintf("%.9f", gravity.MeanAnomaly(0.4, 1+4*math.Pi)))
+		require.Equal(t, fmt.Sprintf("%.9f", 1+4*math.Pi), fmt.Sprintf("%.9f", gravity.TrueAnomaly(0.4, m+4*math.Pi)))
+	})
+	t.Run("succeed in solving the hyperbolic kepler equation", func(t *testing.T) {
+		for _, m := range []float64{-50, -1, 0, 0.5, 10, 1000} {
+			h := gravity.HyperbolicAnomaly(2, m)
+			require.Equal(t, fmt.Sprintf("%.9f", m), fmt.Sprintf("%.9f", 2*math.Sinh(h)-h))
+		}
+	})
+}
+
+func TestTimeOfFlight(t *testing.T) {
+	mu := bodies.Earth.GM
+	t.Run("succeed in taking half a period from periapsis to apoapsis", func(t *testing.T) {
+		a := 20000e3
+		tof := gravity.TimeOfFlight(a, 0.6, 0, math.Pi, mu)
+		require.Equal(t, fmt.Sprintf("%.6f", gravity.PeriodMu(a, mu)/2), fmt.Sprintf("%.6f", tof))
+	})
+	t.Run("succeed in flying forwards around an ellipse", func(t *testing.T) {
+		a := 20000e3
+		tof := gravity.TimeOfFlight(a, 0.6, 1, 0.5, mu)
+		require.Greater(t, tof, gravity.PeriodMu(a, mu)/2)
+		require.Less(t, tof, gravity.PeriodMu(a, mu))
+	})
+	t.Run("succeed in matching StateVectorsMu on an ellipse", func(t *testing.T) {
+		a, e, nu := 9000e3, 0.3, 2.2
+		tof := gravity.TimeOfFlight(a, e, 0, nu, mu)
+		r, _ := gravity.StateVectorsMu(a, e, 0, 0, 0, 0, tof, mu)
+		require.Equal(t, fmt.Sprintf("%.6f", nu), fmt.Sprintf("%.6f", math.Atan2(r[1], r[0])))
+	})
+	t.Run("succeed in matching Propagate on a hyperbola", func(t *testing.T) {
+		a, e, nu := -10000e3, 1.8, 1.9
+		rp := a * (1 - e)
+		vp := math.Sqrt(mu * (1 + e) / rp)
+		tof := gravity.TimeOfFlight(a, e, 0, nu, mu)
+		r, _ := gravity.Propagate(f64.Vec3{rp, 0, 0}, f64.Vec3{0, vp, 0}, tof, mu)
+		require.Equal(t, fmt.Sprintf("%.6f", nu), fmt.Sprintf("%.6f", math.Atan2(r[1], r[0])))
+		require.Equal(t, fmt.Sprintf("%.6f", -tof), fmt.Sprintf("%.6f", gravity.TimeOfFlight(a, e, nu, 0, mu)))
+	})
+	t.Run("succeed in inverting the time of flight", func(t *testing.T) {
+		for _, tc := range []struct{ a, e, nu0, nu1 float64 }{
+			{9000e3, 0.3, 0.2, 2.2},
+			{9000e3, 0.3, 5, 1},
+			{-10000e3, 1.8, -1.5, 1.9},
+		} {
+			tof := gravity.TimeOfFlight(tc.a, tc.e, tc.nu0, tc.nu1, mu)
+			nu := gravity.TrueAnomalyAfter(tc.a, tc.e, tc.nu0, tof, mu)
+			require.Equal(t, fmt.Sprintf("%.9f", tc.nu1), fmt.Sprintf("%.9f", nu))
+		}
+	})
+}
+
+func TestPlaneChange(t *testing.T) {
+	mu := bodies.Earth.GM
+	// burn at true anomaly nu of the first orbit into the plane (lan2, i2)
+	// keeping the radial velocity and optionally circularizing.
+	burn := func(a, e, w, lan1, i1, lan2, i2, nu float64, circularize bool) (dv float64, r, v f64.Vec3) {
+		r, v0 := gravity.StateVectorsMu(a, e, w, lan1, i1, gravity.MeanAnomaly(e, nu), 0, mu)
+		rhat := vec3.Normalize(r)
+		h2 := f64.Vec3{math.Sin(lan2) * math.Sin(i2), -math.Cos(lan2) * math.Sin(i2), math.Cos(i2)}
+		vr := vec3.Dot(v0, rhat)
+		vh := vec3.Magnitude(vec3.Sub(v0, vec3.MulScalar(rhat, vr)))
+		if circularize {
+			vr, vh = 0, math.Sqrt(mu/vec3.Magnitude(r))
+		}
+		v = vec3.Add(vec3.MulScalar(rhat, vr), vec3.MulScalar(vec3.Cross(h2, rhat), vh))
+		return vec3.Magnitude(vec3.Sub(v, v0)), r, v
+	}
+
+	t.Run("succeed in changing the inclination of a circular orbit", func(t *testing.T) {
+		a, di := 7000e3, gravity.Radians(10)
+		dv, nu := gravity.InclinationChange(a, 0, 0.4, 1, 0.5, 0.5+di, mu)
+		require.Equal(t, fmt.Sprintf("%.6f", 2*math.Sqrt(mu/a)*math.Sin(di/2)), fmt.Sprintf("%.6f", dv))
+		require.Equal(t, fmt.Sprintf("%.9f", 2*math.Pi-0.4), fmt.Sprintf("%.9f", nu))
+	})
+	t.Run("succeed in choosing the node furthest from the primary body", func(t *testing.T) {
+		a, e := 20000e3, 0.5
+		dv, nu := gravity.InclinationChange(a, e, 0, 1, 0.5, 0.2, mu)
+		require.Equal(t, fmt.Sprintf("%.9f", math.Pi), fmt.Sprintf("%.9f", nu))
+		want, r, v := burn(a, e, 0, 1, 0.5, 1, 0.2, nu, false)
+		require.Equal(t, fmt.Sprintf("%.6f", want), fmt.Sprintf("%.6f", dv))
+		a2, e2, _, lan2, i2, _ := gravity.OrbitalElementsMu(r, v, mu)
+		require.Equal(t, fmt.Sprintf("%.3f", a), fmt.Sprintf("%.3f", a2))
+		require.Equal(t, fmt.Sprintf("%.9f", e), fmt.Sprintf("%.9f", e2))
+		require.Equal(t, "1.000000", fmt.Sprintf("%.6f", lan2))
+		require.Equal(t, "0.200000", fmt.Sprintf("%.6f", i2))
+	})
+	t.Run("succeed in shifting the node", func(t *testing.T) {
+		a, i := 7000e3, gravity.Radians(45)
+		dv, nu := gravity.NodeShift(a, 0, 0, 0, math.Pi/2, i, mu)
+		// the planes are 60 degrees apart.
+		require.Equal(t, fmt.Sprintf("%.6f", math.Sqrt(mu/a)), fmt.Sprintf("%.6f", dv))
+		_, r, v := burn(a, 0, 0, 0, i, math.Pi/2, i, nu, false)
+		_, _, _, lan2, i2, _ := gravity.OrbitalElementsMu(r, v, mu)
+		require.Equal(t, fmt.Sprintf("%.6f", math.Pi/2), fmt.Sprintf("%.6f", lan2))
+		require.Equal(t, fmt.Sprintf("%.6f", i), fmt.Sprintf("%.6f", i2))
+	})
+	t.Run("succeed in changing plane and circularizing from GTO to GEO", func(t *testing.T) {
+		rp, ra := 6678e3, 42164e3
+		a, e := (rp+ra)/2, (ra-rp)/(ra+rp)
+		dv, nu := gravity.PlaneChangeCircularize(a, e, 0, 0, gravity.Radians(28.5), 0, 0, mu)
+		require.Equal(t, fmt.Sprintf("%.9f", math.Pi), fmt.Sprintf("%.9f", nu))
+		require.Equal(t, "1.830", fmt.Sprintf("%.3f", dv/1000))
+		want, _, _ := burn(a, e, 0, 0, gravity.Radians(28.5), 0, 0, nu, true)
+		require.Equal(t, fmt.Sprintf("%.6f", want), fmt.Sprintf("%.6f", dv))
+	})
+	t.Run("succeed in doing nothing for the same plane", func(t *testing.T) {
+		dv, _ := gravity.PlaneChange(7000e3, 0.1, 0, 1, 0.5, 1, 0.5, mu)
+		require.Equal(t, 0.0, dv)
+	})
+}
+
+func TestPhasing(t *testing.T) {
+	mu := bodies.Earth.GM
+	for _, tc := range []struct {
+		name     string
+		a, e, ph float64
+		revs     int
+	}{
+		{"catch up with a target ahead on a circular orbit", 6778e3, 0, gravity.Radians(10), 1},
+		{"wait for a target behind on an elliptical orbit", 10000e3, 0.2, -0.3, 2},
+		{"take the short way round for a large phase", 6778e3, 0, gravity.Radians(350), 1},
+	} {
+		t.Run("succeed in "+tc.name, func(t *testing.T) {
+			period, wait, dv, err := gravity.Phasing(tc.a, tc.e, tc.ph, tc.revs, mu)
+			require.NoError(t, err)
+			require.Equal(t, float64(tc.revs)*period, wait)
+			// the target arrives at the periapsis of the shared orbit as
+			// the chaser completes its phasing revolutions.
+			m := tc.ph + wait*math.Sqrt(mu/(tc.a*tc.a*tc.a))
+			require.Equal(t, "0.000000", fmt.Sprintf("%.6f", math.Abs(math.Remainder(m, 2*math.Pi))))
+			// the phasing orbit passes through the same periapsis.
+			ap := math.Cbrt(mu * math.Pow(period/(2*gravity.Pi), 2))
+			rp := tc.a * (1 - tc.e)
+			v := math.Sqrt(mu * (2/rp - 1/tc.a))
+			vp := math.Sqrt(mu * (2/rp - 1/ap))
+			require.Equal(t, fmt.Sprintf("%.6f", 2*math.Abs(vp-v)), fmt.Sprintf("%.6f", dv))
+			require.Equal(t, tc.ph > 0 && tc.ph < math.Pi, period < gravity.PeriodMu(tc.a, mu))
+		})
+	}
+	t.Run("succeed in spending less delta-v over more revolutions", func(t *testing.T) {
+		_, _, dv1, err := gravity.Phasing(6778e3, 0, 0.5, 1, mu)
+		require.NoError(t, err)
+		_, _, dv3, err := gravity.Phasing(6778e3, 0, 0.5, 3, mu)
+		require.NoError(t, err)
+		require.Less(t, dv3, dv1)
+	})
+	t.Run("fail for no revolutions", func(t *testing.T) {
+		for _, revs := range []int{0, -1} {
+			_, _, _, err := gravity.Phasing(6778e3, 0, 0.5, revs, mu)
+			require.ErrorIs(t, err, gravity.ErrPhasing)
+		}
+	})
+}
+
+func TestLambert(t *testing.T) {
+	t.Run("succeed in solving the textbook example", func(t *testing.T) {
+		// Curtis, Orbital Mechanics for Engineering Students, example 5.2.
+		r1 := f64.Vec3{5000e3, 10000e3, 2100e3}
+		r2 := f64.Vec3{-14600e3, 2500e3, 7000e3}
+		v1, v2, err := gravity.Lambert(r1, r2, 3600, 398600e9, true)
+		require.NoError(t, err)
+		require.Equal(t, "-5.9925 1.9254 3.2456", fmt.Sprintf("%.4f %.4f %.4f", v1[0]/1000, v1[1]/1000, v1[2]/1000))
+		require.Equal(t, "-3.3125 -4.1966 -0.3853", fmt.Sprintf("%.4f %.4f %.4f", v2[0]/1000, v2[1]/1000, v2[2]/1000))
+	})
+	for _, tc := range []struct {
+		name     string
+		tof      float64
+		prograde bool
+	}{
+		{"a short hyperbolic transfer", 600, true},
+		{"a long elliptical transfer", 20000, true},
+		{"a retrograde transfer", 5000, false},
+	} {
+		t.Run("succeed in matching Propagate for "+tc.name, func(t *testing.T) {
+			mu := bodies.Earth.GM
+			r1, r2 := f64.Vec3{7000e3, 1000e3, 0}, f64.Vec3{-2000e3, 9000e3, 1500e3}
+			v1, v2, err := gravity.Lambert(r1, r2, tc.tof, mu, tc.prograde)
+			require.NoError(t, err)
+			r, v := gravity.Propagate(r1, v1, tc.tof, mu)
+			require.Less(t, vec3.Magnitude(vec3.Sub(r, r2)), 1e-2)
+			require.Less(t, vec3.Magnitude(vec3.Sub(v, v2)), 1e-5)
+			require.Equal(t, tc.prograde, vec3.Cross(r1, v1)[2] > 0)
+		})
+	}
+	t.Run("fail for a non positive time of flight", func(t *testing.T) {
+		_, _, err := gravity.Lambert(f64.Vec3{1, 0, 0}, f64.Vec3{0, 1, 0}, 0, 1, true)
+		require.ErrorIs(t, err, gravity.ErrLambert)
+	})
+	t.Run("fail for opposite positions", func(t *testing.T) {
+		_, _, err := gravity.Lambert(f64.Vec3{7000e3, 0, 0}, f64.Vec3{-8000e3, 0, 0}, 3600, bodies.Earth.GM, true)
+		require.ErrorIs(t, err, gravity.ErrLambert)
+	})
+}
+
+func TestFlyby(t *testing.T) {
+	mu := bodies.Jupiter.GM
+	rp := 5 * bodies.Jupiter.Radius
+	vin := f64.Vec3{-3000, 9000, 0}
+	t.Run("succeed in keeping the hyperbolic excess speed", func(t *testing.T) {
+		vout, _, _ := gravity.Flyby(vin, rp, 0.7, mu)
+		require.Equal(t, fmt.Sprintf("%.6f", vec3.Magnitude(vin)), fmt.Sprintf("%.6f", vec3.Magnitude(vout)))
+	})
+	t.Run("succeed in turning by the turn angle", func(t *testing.T) {
+		vout, delta, dv := gravity.Flyby(vin, rp, 0.7, mu)
+		angle := acos(vec3.Dot(vin, vout) / (vec3.Magnitude(vin) * vec3.Magnitude(vout)))
+		require.Equal(t, fmt.Sprintf("%.9f", delta), fmt.Sprintf("%.9f", angle))
+		require.Equal(t, fmt.Sprintf("%.6f", 2*vec3.Magnitude(vin)*math.Sin(delta/2)), fmt.Sprintf("%.6f", vec3.Magnitude(dv)))
+	})
+	t.Run("succeed in matching the hyperbola of the flyby", func(t *testing.T) {
+		vinf := vec3.Magnitude(vin)
+		e := 1 + rp*vinf*vinf/mu
+		// asymptotes of a hyperbola are 2 acos(-1/e) apart.
+		require.Equal(t, fmt.Sprintf("%.9f", 2*math.Acos(-1/e)-math.Pi), fmt.Sprintf("%.9f", gravity.TurnAngle(vinf, rp, mu)))
+	})
+	t.Run("succeed in staying in the ecliptic for a zero B-plane angle", func(t *testing.T) {
+		vout, _, _ := gravity.Flyby(vin, rp, 0, mu)
+		require.Equal(t, "0.000000", fmt.Sprintf("%.6f", math.Abs(vout[2])))
+		vout, _, _ = gravity.Flyby(vin, rp, math.Pi/2, mu)
+		require.Greater(t, math.Abs(vout[2]), 1000.0)
+	})
+	t.Run("succeed in speeding up behind the planet and slowing in front", func(t *testing.T) {
+		// The planet moves along y and T = x cross z = -y so a B-plane
+		// angle of 0 passes behind the planet and bends the trajectory
+		// forwards along its motion.
+		vp, v := f64.Vec3{0, 13000, 0}, f64.Vec3{5000, 0, 0}
+		_, _, dv := gravity.Flyby(v, rp, 0, mu)
+		require.Greater(t, vec3.Magnitude(vec3.Add(vec3.Add(vp, v), dv)), vec3.Magnitude(vec3.Add(vp, v)))
+		_, _, dv = gravity.Flyby(v, rp, math.Pi, mu)
+		require.Less(t, vec3.Magnitude(vec3.Add(vec3.Add(vp, v), dv)), vec3.Magnitude(vec3.Add(vp, v)))
+	})
+	t.Run("succeed in finding the periapsis for a turn angle", func(t *testing.T) {
+		vinf := vec3.Magnitude(vin)
+		delta := gravity.TurnAngle(vinf, rp, mu)
+		require.Equal(t, fmt.Sprintf("%.3f", rp), fmt.Sprintf("%.3f", gravity.FlybyPeriapsis(vinf, delta, mu)))
+	})
+	t.Run("succeed in handling an approach along the z axis", func(t *testing.T) {
+		vout, delta, _ := gravity.Flyby(f64.Vec3{0, 0, -5000}, rp, 0, mu)
+		require.Equal(t, fmt.Sprintf("%.6f", 5000*math.Sin(delta)), fmt.Sprintf("%.6f", math.Hypot(vout[0], vout[1])))
+	})
+}
+
+func acos(x float64) float64 {
+	return math.Acos(math.Max(-1, math.Min(1, x)))
+}
@@ -0,0 +1,89 @@
+package gravity
+
+import (
+	"errors"
+	"math"
+
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+var ErrLambert = errors.New("gravity: no lambert solution")
+
+// Lambert velocities of the conic from r1 to r2 in tof seconds.
+//
+// accepts:
+// r1:       initial position relative to primary body (m),
+// r2:       final position relative to primary body   (m),
+// tof:      time of flight                            (s),
+// mu:       standard gravitational parameter          (m^3/s^2),
+// prograde: whether to travel counter-clockwise about the z axis.
+//
+// returns:
+// v1: velocity at r1                                  (m/s),
+// v2: velocity at r2                                  (m/s).
+//
+// Only transfers of less than one revolution are found. ErrLambert is
+// returned when the time of flight is not positive or the positions are
+// exactly opposite each other, since the plane of the transfer is then
+// undefined.
+//
+// https://en.wikipedia.org/wiki/Lambert%27s_problem
+func Lambert(r1, r2 f64.Vec3, tof, mu float64, prograde bool) (v1, v2 f64.Vec3, err error) {
+	if !(tof > 0) {
+		return v1, v2, ErrLambert
+	}
+	m1, m2 := vec3.Magnitude(r1), vec3.Magnitude(r2)
+	dtheta := acos(vec3.Dot(r1, r2) / (m1 * m2))
+	if cz := vec3.Cross(r1, r2)[2]; (prograde && cz < 0) || (!prograde && cz >= 0) {
+		dtheta = 2*math.Pi - dtheta
+	}
+	a := math.Sin(dtheta) * math.Sqrt(m1*m2/(1-math.Cos(dtheta)))
+	if math.Abs(a) < Epsilon6 || math.IsNaN(a) {
+		return v1, v2, ErrLambert
+	}
+
+	y := func(z float64) float64 {
+		c, s := stumpff(z)
+		return m1 + m2 + a*(z*s-1)/math.Sqrt(c)
+	}
+	// time of flight for z which increases with z, and is -Inf where y is
+	// negative and the conic does not exist.
+	time := func(z float64) float64 {
+		yz := y(z)
+		if yz < 0 {
+			return math.Inf(-1)
+		}
+		c, s := stumpff(z)
+		return (math.Pow(yz/c, 1.5)*s + a*math.Sqrt(yz)) / math.Sqrt(mu)
+	}
+
+	// z is bracketed by hyperbolas far below zero and the single
+	// revolution limit of (2Pi)^2 above.
+	lo, hi := -4*math.Pi*math.Pi, 4*math.Pi*math.Pi*(1-Epsilon6)
+	for time(lo) > tof {
+		lo *= 2
+		if math.IsInf(lo, 0) {
+			return v1, v2, ErrLambert
+		}
+	}
+	if time(hi) < tof {
+		return v1, v2, ErrLambert
+	}
+	for k := 0; k < 200 && hi-lo > 1e-12*math.Max(1, math.Abs(lo)); k++ {
+		mid := (lo + hi) / 2
+		if time(mid) < tof {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	yz := y((lo + hi) / 2)
+
+	f := 1 - yz/m1
+	g := a * math.Sqrt(yz/mu)
+	gdot := 1 - yz/m2
+	v1 = vec3.DivScalar(vec3.Sub(r2, vec3.MulScalar(r1, f)), g)
+	v2 = vec3.DivScalar(vec3.Sub(vec3.MulScalar(r2, gdot), r1), g)
+	return v1, v2, nil
+}
@@ -0,0 +1,50 @@
+package gravity
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrPhasing = errors.New("gravity: phasing revolutions must be greater than 0")
+
+// Phasing orbit for a chaser to meet a target on the same orbit.
+//
+// accepts:
+// a:     semi-major axis of the shared orbit                      (m),
+// e:     eccentricity of the shared orbit                         (0-1),
+// phase: mean anomaly of the target minus that of the chaser      (rad),
+// revs:  revolutions the chaser spends on the phasing orbit,
+// mu:    standard gravitational parameter                         (m^3/s^2).
+//
+// returns:
+// period: period of the phasing orbit                             (s),
+// wait:   time from entering to leaving the phasing orbit         (s),
+// dv:     total delta-v of entering and leaving the phasing orbit (m/s).
+//
+// The chaser burns at periapsis of the shared orbit onto a phasing orbit
+// which shares that point and after revs revolutions burns back meeting
+// the target. A target ahead (positive phase) needs a shorter phasing
+// orbit and a target behind a longer one. The phase is taken the short
+// way round. Spreading the phasing over more revolutions costs less
+// delta-v but takes longer. A short phasing orbit may dip below the
+// surface of the primary body so check its periapsis.
+//
+// The chaser is assumed to be at periapsis so wait does not include any
+// coast to the first burn. Otherwise add the time to reach periapsis, see
+// TimeOfFlight. The phase does not change while coasting since both share
+// the orbit. ErrPhasing is returned when revs is not positive.
+//
+// https://en.wikipedia.org/wiki/Orbit_phasing
+func Phasing(a, e, phase float64, revs int, mu float64) (period, wait, dv float64, err error) {
+	if revs <= 0 {
+		return 0, 0, 0, ErrPhasing
+	}
+	phase = math.Remainder(phase, 2*Pi)
+	k := float64(revs)
+	period = PeriodMu(a, mu) * (1 - phase/(2*Pi*k))
+	ap := math.Cbrt(mu * (period / (2 * Pi)) * (period / (2 * Pi)))
+	rp := a * (1 - e)
+	v := math.Sqrt(mu * (2/rp - 1/a))
+	vp := math.Sqrt(mu * (2/rp - 1/ap))
+	return period, k * period, 2 * math.Abs(vp-v), nil
+}
@@ -0,0 +1,96 @@
+package gravity
+
+import (
+	"math"
+
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+// InclinationChange burn rotating an orbit from inclination i1 to i2 at
+// the cheaper of its nodes. See PlaneChange for more details.
+func InclinationChange(a, e, w, lan, i1, i2, mu float64) (dv, nu float64) {
+	return PlaneChange(a, e, w, lan, i1, lan, i2, mu)
+}
+
+// NodeShift burn rotating an orbit from longitude of ascending node lan1
+// to lan2 keeping its inclination at the cheaper of the two points where
+// the planes intersect. See PlaneChange for more details.
+func NodeShift(a, e, w, lan1, lan2, i, mu float64) (dv, nu float64) {
+	return PlaneChange(a, e, w, lan1, i, lan2, i, mu)
+}
+
+// PlaneChange burn rotating an orbit into a new plane without changing
+// its shape.
+//
+// accepts:
+// a:    semi-major axis                        (m),
+// e:    eccentricity                           (0-1),
+// w:    argument of periapsis                  (rad),
+// lan1: longitude of ascending node            (rad),
+// i1:   inclination                            (rad),
+// lan2: target longitude of ascending node     (rad),
+// i2:   target inclination                     (rad),
+// mu:   standard gravitational parameter       (m^3/s^2).
+//
+// returns:
+// dv: delta-v of the burn                      (m/s),
+// nu: true anomaly of the burn                 (rad).
+//
+// The burn happens where the planes intersect which is at two points half
+// an orbit apart. Only the horizontal velocity is rotated so the point
+// furthest from the primary body is cheaper and is chosen. The elements
+// match those of OrbitalElements so lan and i may be taken directly from
+// its output.
+//
+// https://en.wikipedia.org/wiki/Orbital_inclination_change
+func PlaneChange(a, e, w, lan1, i1, lan2, i2, mu float64) (dv, nu float64) {
+	return planeChange(a, e, w, lan1, i1, lan2, i2, mu, false)
+}
+
+// PlaneChangeCircularize burn rotating an orbit into a new plane and
+// making it circular at the radius of the burn, as when going from a
+// geostationary transfer orbit to a geostationary orbit. The cheaper of
+// the two points where the planes intersect is chosen. See PlaneChange
+// for more details.
+func PlaneChangeCircularize(a, e, w, lan1, i1, lan2, i2, mu float64) (dv, nu float64) {
+	return planeChange(a, e, w, lan1, i1, lan2, i2, mu, true)
+}
+
+func planeChange(a, e, w, lan1, i1, lan2, i2, mu float64, circularize bool) (dv, nu float64) {
+	h1, h2 := planeNormal(lan1, i1), planeNormal(lan2, i2)
+	line := vec3.Cross(h1, h2)
+	theta := math.Atan2(vec3.Magnitude(line), vec3.Dot(h1, h2))
+
+	// Argument of latitude of the line of intersection measured from the
+	// ascending node in the initial plane, which is arbitrary when the
+	// planes are the same.
+	u := 0.0
+	if vec3.Magnitude(line) > Epsilon {
+		node := f64.Vec3{math.Cos(lan1), math.Sin(lan1), 0}
+		u = math.Atan2(vec3.Dot(vec3.Cross(node, line), h1), vec3.Dot(node, line))
+	}
+
+	p := a * (1 - e*e)
+	dv = math.Inf(1)
+	for _, nuk := range []float64{u - w, u - w + math.Pi} {
+		nuk -= math.Floor(nuk/(2*math.Pi)) * 2 * math.Pi
+		r := p / (1 + e*math.Cos(nuk))
+		vr := math.Sqrt(mu/p) * e * math.Sin(nuk)
+		vh := math.Sqrt(mu*p) / r
+		dvk := 2 * vh * math.Sin(theta/2)
+		if circularize {
+			vc := math.Sqrt(mu / r)
+			dvk = math.Sqrt(vr*vr + vh*vh + vc*vc - 2*vh*vc*math.Cos(theta))
+		}
+		if dvk < dv-Epsilon6 {
+			dv, nu = dvk, nuk
+		}
+	}
+	return dv, nu
+}
+
+// planeNormal unit angular momentum of an orbit.
+func planeNormal(lan, i float64) f64.Vec3 {
+	return f64.Vec3{math.Sin(lan) * math.Sin(i), -math.Cos(lan) * math.Sin(i), math.Cos(i)}
+}
@@ -0,0 +1,128 @@
+package gravity
+
+import (
+	"runtime"
+	"sync"
+
+	"golang.org/x/image/math/f64"
+)
+
+// Batch of many independent orbits stored in structure-of-arrays layout
+// with the time independent terms of StateVectorsMu precomputed.
+//
+// A Batch is read only once created and is safe for concurrent use.
+type Batch struct {
+	a, e, m0, n       []float64
+	sqrt1pe, sqrt1me  []float64
+	sqrt1mee, sqrtmua []float64
+	px, py, pz        []float64
+	qx, qy, qz        []float64
+}
+
+// NewBatch of orbits from slices of Keplerian Orbital Elements where
+// element k of every slice describes orbit k.
+//
+// accepts:
+// a:   semi-major axes                   (m),
+// e:   eccentricities                    (0-1),
+// w:   arguments of periapsis            (rad),
+// lan: longitudes of ascending node      (rad),
+// i:   inclinations                      (rad),
+// m0:  mean anomalies at epoch           (rad),
+// mu:  standard gravitational parameters (m^3/s^2).
+//
+// All slices must have the same length. See StateVectorsMu for more
+// details.
+func NewBatch(a, e, w, lan, i, m0, mu []float64) *Batch {
+	n := len(a)
+	if len(e) != n || len(w) != n || len(lan) != n || len(i) != n || len(m0) != n || len(mu) != n {
+		panic("gravity: NewBatch slices have different lengths")
+	}
+	b := &Batch{
+		a:        make([]float64, n),
+		e:        make([]float64, n),
+		m0:       make([]float64, n),
+		n:        make([]float64, n),
+		sqrt1pe:  make([]float64, n),
+		sqrt1me:  make([]float64, n),
+		sqrt1mee: make([]float64, n),
+		sqrtmua:  make([]float64, n),
+		px:       make([]float64, n),
+		py:       make([]float64, n),
+		pz:       make([]float64, n),
+		qx:       make([]float64, n),
+		qy:       make([]float64, n),
+		qz:       make([]float64, n),
+	}
+	for k := 0; k < n; k++ {
+		o := newOrbit(a[k], e[k], w[k], lan[k], i[k], m0[k], mu[k])
+		b.a[k], b.e[k], b.m0[k], b.n[k] = o.a, o.e, o.m0, o.n
+		b.sqrt1pe[k], b.sqrt1me[k] = o.sqrt1pe, o.sqrt1me
+		b.sqrt1mee[k], b.sqrtmua[k] = o.sqrt1mee, o.sqrtmua
+		b.px[k], b.py[k], b.pz[k] = o.p[0], o.p[1], o.p[2]
+		b.qx[k], b.qy[k], b.qz[k] = o.q[0], o.q[1], o.q[2]
+	}
+	return b
+}
+
+// Len number of orbits in the batch.
+func (b *Batch) Len() int {
+	return len(b.a)
+}
+
+// StateVectors of every orbit at t seconds after epoch.
+//
+// r: destination for positions  (m),
+// v: destination for velocities (m/s).
+//
+// r and v must be at least Len long. No memory is allocated.
+func (b *Batch) StateVectors(t float64, r, v []f64.Vec3) {
+	b.propagate(0, b.Len(), t, r, v)
+}
+
+// StateVectorsParallel of every orbit at t seconds after epoch split
+// across workers goroutines. If workers is less than 1 then
+// runtime.GOMAXPROCS(0) is used. See StateVectors for more details.
+func (b *Batch) StateVectorsParallel(t float64, r, v []f64.Vec3, workers int) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	n := b.Len()
+	chunk := (n + workers - 1) / workers
+	if chunk == 0 {
+		return
+	}
+	wg := sync.WaitGroup{}
+	for lo := 0; lo < n; lo += chunk {
+		hi := lo + chunk
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			b.propagate(lo, hi, t, r, v)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
+
+func (b *Batch) propagate(lo, hi int, t float64, r, v []f64.Vec3) {
+	r, v = r[lo:hi], v[lo:hi]
+	for k := range r {
+		j := lo + k
+		o := orbit{
+			a:        b.a[j],
+			e:        b.e[j],
+			m0:       b.m0[j],
+			n:        b.n[j],
+			sqrt1pe:  b.sqrt1pe[j],
+			sqrt1me:  b.sqrt1me[j],
+			sqrt1mee: b.sqrt1mee[j],
+			sqrtmua:  b.sqrtmua[j],
+			p:        f64.Vec3{b.px[j], b.py[j], b.pz[j]},
+			q:        f64.Vec3{b.qx[j], b.qy[j], b.qz[j]},
+		}
+		r[k], v[k] = o.at(t)
+	}
+}
@@ -0,0 +1,32 @@
+package gravity
+
+import "math"
+
+// LaplaceSOI radius of the Laplace sphere of influence (m).
+//
+// a:  semi-major axis of the secondary around the primary (m),
+// m1: mass of the primary body                            (kg),
+// m2: mass of the secondary body                          (kg).
+//
+// Only the ratio of the masses is used so standard gravitational
+// parameters (m^3/s^2) may be passed in place of masses.
+//
+// https://en.wikipedia.org/wiki/Sphere_of_influence_(astrodynamics)
+func LaplaceSOI(a, m1, m2 float64) float64 {
+	return a * math.Pow(m2/m1, 2.0/5.0)
+}
+
+// HillSphere radius (m) at the periapsis of the secondary.
+//
+// a:  semi-major axis of the secondary around the primary (m),
+// e:  eccentricity of the secondary                       (0-1),
+// m1: mass of the primary body                            (kg),
+// m2: mass of the secondary body                          (kg).
+//
+// Only the ratio of the masses is used so standard gravitational
+// parameters (m^3/s^2) may be passed in place of masses.
+//
+// https://en.wikipedia.org/wiki/Hill_sphere
+func HillSphere(a, e, m1, m2 float64) float64 {
+	return a * (1 - e) * math.Cbrt(m2/(3*m1))
+}
@@ -0,0 +1,27 @@
+package gravity
+
+import "math"
+
+// Hohmann transfer between two coplanar circular orbits.
+//
+// accepts:
+// r1: radius of the initial orbit         (m),
+// r2: radius of the target orbit          (m),
+// mu: standard gravitational parameter    (m^3/s^2).
+//
+// returns:
+// dv1: delta-v of the departure burn      (m/s),
+// dv2: delta-v of the arrival burn        (m/s),
+// tof: time of flight                     (s).
+//
+// Both burns are along the velocity when raising the orbit and against it
+// when lowering so the delta-v values are always positive.
+//
+// https://en.wikipedia.org/wiki/Hohmann_transfer_orbit
+func Hohmann(r1, r2, mu float64) (dv1, dv2, tof float64) {
+	a := (r1 + r2) / 2
+	dv1 = math.Abs(math.Sqrt(mu/r1) * (math.Sqrt(r2/a) - 1))
+	dv2 = math.Abs(math.Sqrt(mu/r2) * (1 - math.Sqrt(r1/a)))
+	tof = PeriodMu(a, mu) / 2
+	return
+}
@@ -0,0 +1,132 @@
+package gravity
+
+import (
+	"math"
+
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+// Propagate state vectors t seconds along their conic using the universal
+// variable formulation of Kepler's equation.
+//
+// accepts:
+// r:  position relative to primary body (m),
+// v:  velocity relative to primary body (m/s),
+// t:  time to propagate, may be negative (s),
+// mu: standard gravitational parameter  (m^3/s^2).
+//
+// returns:
+// r:  position relative to primary body (m),
+// v:  velocity relative to primary body (m/s).
+//
+// Unlike StateVectorsMu this works for elliptic, parabolic and hyperbolic
+// orbits alike and does not go through the orbital elements so it has
+// none of their singularities.
+//
+// https://en.wikipedia.org/wiki/Universal_variable_formulation
+func Propagate(r, v f64.Vec3, t, mu float64) (f64.Vec3, f64.Vec3) {
+	if t == 0 {
+		return r, v
+	}
+	r0 := vec3.Magnitude(r)
+	v0 := vec3.Magnitude(v)
+	rv := vec3.Dot(r, v)
+	sqrtmu := math.Sqrt(mu)
+	alpha := 2/r0 - v0*v0/mu // reciprocal of the semi-major axis
+
+	// Whole revolutions of an ellipse change nothing so remove them to
+	// keep the iteration well conditioned.
+	if alpha > Epsilon6/r0 {
+		period := 2 * math.Pi / (sqrtmu * math.Sqrt(alpha*alpha*alpha))
+		t = math.Mod(t, period)
+	}
+
+	// kepler is the universal Kepler equation which increases
+	// monotonically with chi since its derivative is the radius.
+	kepler := func(chi float64) (f, df float64) {
+		z := alpha * chi * chi
+		c, s := stumpff(z)
+		chi2 := chi * chi
+		f = rv/sqrtmu*chi2*c + (1-alpha*r0)*chi2*chi*s + r0*chi - sqrtmu*t
+		df = rv/sqrtmu*chi*(1-z*s) + (1-alpha*r0)*chi2*c + r0
+		return f, df
+	}
+
+	// Bracket the root then polish it with Newton's method falling back
+	// to bisection whenever a step leaves the bracket.
+	chi := initialChi(r0, rv, alpha, t, mu)
+	lo, hi := math.Min(chi, 0), math.Max(chi, 0)
+	for f, _ := kepler(hi); f < 0; f, _ = kepler(hi) {
+		lo, hi = hi, 2*hi+r0/sqrtmu
+	}
+	for f, _ := kepler(lo); f > 0; f, _ = kepler(lo) {
+		lo, hi = 2*lo-r0/sqrtmu, lo
+	}
+	for k := 0; k < 200; k++ {
+		f, df := kepler(chi)
+		if f == 0 {
+			break
+		}
+		if f < 0 {
+			lo = chi
+		} else {
+			hi = chi
+		}
+		next := chi - f/df
+		if !(next > lo && next < hi) {
+			next = (lo + hi) / 2
+		}
+		d := next - chi
+		chi = next
+		if math.Abs(d) <= 1e-12*math.Max(1, math.Abs(chi)) {
+			break
+		}
+	}
+	z := alpha * chi * chi
+	c, s := stumpff(z)
+
+	chi2 := chi * chi
+	f := 1 - chi2/r0*c
+	g := t - chi2*chi*s/sqrtmu
+	rt := vec3.Add(vec3.MulScalar(r, f), vec3.MulScalar(v, g))
+	rtmag := vec3.Magnitude(rt)
+	df := sqrtmu / (rtmag * r0) * (z*s - 1) * chi
+	dg := 1 - chi2/rtmag*c
+	vt := vec3.Add(vec3.MulScalar(r, df), vec3.MulScalar(v, dg))
+	return rt, vt
+}
+
+// initialChi guess of the universal anomaly (sqrt(m)).
+//
+// https://celestrak.org/software/vallado-sw.php
+func initialChi(r0, rv, alpha, t, mu float64) float64 {
+	sqrtmu := math.Sqrt(mu)
+	switch {
+	case alpha > Epsilon6/r0:
+		return sqrtmu * t * alpha
+	case alpha < -Epsilon6/r0:
+		a := 1 / alpha
+		sign := math.Copysign(1, t)
+		chi := sign * math.Sqrt(-a) * math.Log(-2*mu*alpha*t/(rv+sign*math.Sqrt(-mu*a)*(1-r0*alpha)))
+		if !math.IsNaN(chi) && !math.IsInf(chi, 0) {
+			return chi
+		}
+	}
+	return sqrtmu * t / r0
+}
+
+// stumpff functions C(z) and S(z).
+//
+// https://en.wikipedia.org/wiki/Stumpff_function
+func stumpff(z float64) (c, s float64) {
+	switch {
+	case z > Epsilon6:
+		sz := math.Sqrt(z)
+		return (1 - math.Cos(sz)) / z, (sz - math.Sin(sz)) / (sz * sz * sz)
+	case z < -Epsilon6:
+		sz := math.Sqrt(-z)
+		return (math.Cosh(sz) - 1) / -z, (math.Sinh(sz) - sz) / (sz * sz * sz)
+	}
+	return 1.0/2 - z/24 + z*z/720, 1.0/6 - z/120 + z*z/5040
+}
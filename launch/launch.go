@@ -0,0 +1,74 @@
+// Package launch finds launch windows when a site on a rotating body
+// passes through the plane of a target orbit.
+package launch
+
+import (
+	"math"
+	"sort"
+)
+
+// Window when the launch site lies in the plane of the target orbit.
+//
+// T:         time since epoch                                  (s),
+// Azimuth:   inertial launch azimuth clockwise from north      (rad),
+// Ascending: whether the site is on the northbound half of the orbit.
+//
+// Ascending windows launch north-east into prograde orbits and descending
+// windows south-east. The azimuth ignores the rotation of the body, which
+// must be accounted for when targeting a velocity relative to the surface.
+type Window struct {
+	T         float64
+	Azimuth   float64
+	Ascending bool
+}
+
+// Windows from t0 to t1 seconds after epoch.
+//
+// lat:    geocentric latitude of the site                 (rad),
+// lon:    longitude of the site in the body-fixed frame   (rad),
+// rate:   rotation rate of the body                       (rad/s),
+// theta0: rotation angle of the body at epoch             (rad),
+// lan:    longitude of ascending node of the target orbit (rad),
+// i:      inclination of the target orbit                 (rad),
+// t0:     time since epoch to search from                 (s),
+// t1:     time since epoch to search until                (s).
+//
+// The site and body follow the conventions of
+// frames.BodyFixedToInertial. There are two windows per rotation of the
+// body, which merge into one when the latitude equals the inclination,
+// and none when the latitude is higher than the inclination or lower
+// than its negative, in which case the orbit can not be reached without a
+// plane change. The windows are returned in time order.
+func Windows(lat, lon, rate, theta0, lan, i, t0, t1 float64) []Window {
+	windows := []Window{}
+	// The site with inertial longitude alpha is in the plane when
+	// sin(alpha - lan) = tan(lat) / tan(i).
+	x := math.Tan(lat) / math.Tan(i)
+	if math.Abs(x) > 1 || rate == 0 {
+		return windows
+	}
+	u := math.Asin(x)
+	az := math.Asin(math.Max(-1, math.Min(1, math.Cos(i)/math.Cos(lat))))
+	period := 2 * math.Pi / math.Abs(rate)
+	for _, w := range []Window{
+		{T: timeOfLongitude(lan+u, lon, rate, theta0, t0), Azimuth: az, Ascending: true},
+		{T: timeOfLongitude(lan+math.Pi-u, lon, rate, theta0, t0), Azimuth: math.Pi - az, Ascending: false},
+	} {
+		for ; w.T <= t1; w.T += period {
+			windows = append(windows, w)
+		}
+		if x == 1 || x == -1 {
+			break
+		}
+	}
+	sort.Slice(windows, func(a, b int) bool { return windows[a].T < windows[b].T })
+	return windows
+}
+
+// timeOfLongitude first time at or after t0 the site reaches the inertial
+// longitude alpha.
+func timeOfLongitude(alpha, lon, rate, theta0, t0 float64) float64 {
+	period := 2 * math.Pi / math.Abs(rate)
+	t := (alpha - lon - theta0) / rate
+	return t + math.Ceil((t0-t)/period)*period
+}
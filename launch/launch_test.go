@@ -0,0 +1,65 @@
+package launch_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/frames"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/launch"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+var (
+	lat  = gravity.Radians(28.5)
+	lon  = gravity.Radians(-80.6)
+	rate = bodies.Earth.RotationRate()
+	lan  = gravity.Radians(120)
+	inc  = gravity.Radians(51.6)
+)
+
+// site inertial position t seconds after epoch.
+func site(t float64) f64.Vec3 {
+	r := f64.Vec3{math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)}
+	r, _ = frames.BodyFixedToInertial(r, f64.Vec3{}, rate, t, 0.3)
+	return r
+}
+
+func TestWindows(t *testing.T) {
+	h := f64.Vec3{math.Sin(lan) * math.Sin(inc), -math.Cos(lan) * math.Sin(inc), math.Cos(inc)}
+	t.Run("succeed in finding two windows per day in the orbit plane", func(t *testing.T) {
+		windows := launch.Windows(lat, lon, rate, 0.3, lan, inc, 1000, 1000+86164)
+		require.Len(t, windows, 2)
+		require.NotEqual(t, windows[0].Ascending, windows[1].Ascending)
+		for _, w := range windows {
+			require.GreaterOrEqual(t, w.T, 1000.0)
+			require.Equal(t, "0.000000", fmt.Sprintf("%.6f", math.Abs(vec3.Dot(site(w.T), h))))
+			// northbound windows are on the ascending half of the orbit.
+			north := vec3.Dot(vec3.Cross(h, site(w.T)), f64.Vec3{0, 0, 1}) > 0
+			require.Equal(t, w.Ascending, north)
+		}
+	})
+	t.Run("succeed in calculating the launch azimuth", func(t *testing.T) {
+		windows := launch.Windows(lat, lon, rate, 0.3, lan, inc, 0, 86164)
+		for _, w := range windows {
+			if w.Ascending {
+				require.Equal(t, "44.98", fmt.Sprintf("%.2f", gravity.Degrees(w.Azimuth)))
+			} else {
+				require.Equal(t, "135.02", fmt.Sprintf("%.2f", gravity.Degrees(w.Azimuth)))
+			}
+		}
+	})
+	t.Run("succeed in repeating every sidereal day", func(t *testing.T) {
+		windows := launch.Windows(lat, lon, rate, 0.3, lan, inc, 0, 3*86164.0905)
+		require.Len(t, windows, 6)
+		require.Equal(t, "86164.09", fmt.Sprintf("%.2f", windows[2].T-windows[0].T))
+	})
+	t.Run("succeed in finding no windows above the inclination", func(t *testing.T) {
+		windows := launch.Windows(gravity.Radians(60), lon, rate, 0.3, lan, inc, 0, 86400)
+		require.Len(t, windows, 0)
+	})
+}
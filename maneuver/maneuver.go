@@ -0,0 +1,123 @@
+// Package maneuver applies impulsive burns to orbits and accounts for the
+// propellant they consume using the Tsiolkovsky rocket equation.
+package maneuver
+
+import (
+	"github.com/wafer-bw/gorbit/frames"
+	"github.com/wafer-bw/gorbit/orbit"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+// Frame the components of a Maneuver are expressed in.
+type Frame int
+
+const (
+	// RTN radial, transverse and normal components. See
+	// frames.InertialToRTN for more details.
+	RTN Frame = iota
+	// VNB prograde, normal and radial components where prograde is along
+	// the velocity, normal along the angular momentum and radial completes
+	// the right handed set pointing away from the primary body.
+	VNB
+	// Inertial components in the frame of the orbit.
+	Inertial
+)
+
+// Maneuver impulsive change in velocity.
+//
+// T:     time since the epoch of the orbit (s),
+// DV:    delta-v components in Frame        (m/s),
+// Frame: frame of the components.
+type Maneuver struct {
+	T     float64
+	DV    f64.Vec3
+	Frame Frame
+}
+
+// Prograde maneuver of dv (m/s) at t seconds since epoch, negative to
+// burn retrograde.
+func Prograde(t, dv float64) Maneuver {
+	return Maneuver{T: t, DV: f64.Vec3{dv, 0, 0}, Frame: VNB}
+}
+
+// Normal maneuver of dv (m/s) at t seconds since epoch, negative to burn
+// anti-normal.
+func Normal(t, dv float64) Maneuver {
+	return Maneuver{T: t, DV: f64.Vec3{0, dv, 0}, Frame: VNB}
+}
+
+// Radial maneuver of dv (m/s) at t seconds since epoch, negative to burn
+// towards the primary body.
+func Radial(t, dv float64) Maneuver {
+	return Maneuver{T: t, DV: f64.Vec3{0, 0, dv}, Frame: VNB}
+}
+
+// Magnitude of the delta-v (m/s).
+func (m Maneuver) Magnitude() float64 {
+	return vec3.Magnitude(m.DV)
+}
+
+// Inertial delta-v (m/s) of the maneuver performed at the state (r, v).
+func (m Maneuver) Inertial(r, v f64.Vec3) f64.Vec3 {
+	switch m.Frame {
+	case RTN:
+		return frames.RTNToInertial(m.DV, r, v)
+	case VNB:
+		vhat := vec3.Normalize(v)
+		nhat := vec3.Normalize(vec3.Cross(r, v))
+		bhat := vec3.Cross(vhat, nhat)
+		return vec3.Add(vec3.Add(
+			vec3.MulScalar(vhat, m.DV[0]),
+			vec3.MulScalar(nhat, m.DV[1])),
+			vec3.MulScalar(bhat, m.DV[2]),
+		)
+	}
+	return m.DV
+}
+
+// Apply the maneuver to the state. The time of the state is used in
+// place of T.
+func (m Maneuver) Apply(s orbit.State) orbit.State {
+	s.V = vec3.Add(s.V, m.Inertial(s.R, s.V))
+	return s
+}
+
+// ApplyElements performs the maneuver T seconds after the epoch of el and
+// returns the resulting elements with their epoch at the time of the
+// maneuver.
+func (m Maneuver) ApplyElements(el orbit.Elements) orbit.Elements {
+	return m.Apply(el.State(m.T)).Elements()
+}
+
+// Plan of maneuvers in time order with T measured from the epoch of the
+// initial orbit.
+type Plan []Maneuver
+
+// DeltaV total of the plan (m/s).
+func (p Plan) DeltaV() float64 {
+	dv := 0.0
+	for _, m := range p {
+		dv += m.Magnitude()
+	}
+	return dv
+}
+
+// Execute the plan starting from el burning propellant from vehicle. The
+// elements after every maneuver are returned. If the vehicle runs out of
+// propellant the maneuvers performed so far are returned with
+// ErrPropellant. See Maneuver.ApplyElements for more details.
+func (p Plan) Execute(el orbit.Elements, vehicle *Vehicle) ([]orbit.Elements, error) {
+	out := make([]orbit.Elements, 0, len(p))
+	t := 0.0
+	for _, m := range p {
+		if _, err := vehicle.Burn(m.Magnitude()); err != nil {
+			return out, err
+		}
+		m.T -= t
+		el = m.ApplyElements(el)
+		t += m.T
+		out = append(out, el)
+	}
+	return out, nil
+}
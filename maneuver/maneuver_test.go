@@ -0,0 +1,116 @@
+package maneuver_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/epoch"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/maneuver"
+	"github.com/wafer-bw/gorbit/orbit"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+var leo = orbit.Elements{A: 6678e3, E: 0, W: 0.3, LAN: 1.1, I: 0.5, M: 0, Mu: bodies.Earth.GM, Epoch: epoch.J2000}
+
+func TestInertial(t *testing.T) {
+	r, v := f64.Vec3{7000e3, 0, 0}, f64.Vec3{0, 7000, 1000}
+	t.Run("succeed in burning prograde along the velocity", func(t *testing.T) {
+		dv := maneuver.Prograde(0, 10).Inertial(r, v)
+		require.Equal(t, fmt.Sprintf("%.9f", vec3.MulScalar(vec3.Normalize(v), 10)), fmt.Sprintf("%.9f", dv))
+	})
+	t.Run("succeed in burning normal along the angular momentum", func(t *testing.T) {
+		dv := maneuver.Normal(0, 10).Inertial(r, v)
+		require.Equal(t, fmt.Sprintf("%.9f", vec3.MulScalar(vec3.Normalize(vec3.Cross(r, v)), 10)), fmt.Sprintf("%.9f", dv))
+	})
+	t.Run("succeed in burning radial away from the primary body", func(t *testing.T) {
+		dv := maneuver.Radial(0, 10).Inertial(r, v)
+		require.Equal(t, "10.000000", fmt.Sprintf("%.6f", dv[0]))
+		require.Equal(t, "0.000000", fmt.Sprintf("%.6f", vec3.Dot(dv, v)))
+	})
+	t.Run("succeed in burning in the RTN frame", func(t *testing.T) {
+		dv := maneuver.Maneuver{DV: f64.Vec3{1, 2, 3}, Frame: maneuver.RTN}.Inertial(r, v)
+		require.Equal(t, "1.000000", fmt.Sprintf("%.6f", dv[0]))
+		require.Equal(t, fmt.Sprintf("%.6f", math.Sqrt(14)), fmt.Sprintf("%.6f", vec3.Magnitude(dv)))
+	})
+	t.Run("succeed in passing inertial components through", func(t *testing.T) {
+		dv := maneuver.Maneuver{DV: f64.Vec3{1, 2, 3}, Frame: maneuver.Inertial}.Inertial(r, v)
+		require.Equal(t, f64.Vec3{1, 2, 3}, dv)
+	})
+}
+
+func TestApplyElements(t *testing.T) {
+	t.Run("succeed in raising apoapsis with a prograde burn", func(t *testing.T) {
+		dv1, _, _ := gravity.Hohmann(leo.A, 42164e3, leo.Mu)
+		el := maneuver.Prograde(600, dv1).ApplyElements(leo)
+		require.Equal(t, "42164", fmt.Sprintf("%.0f", gravity.Apoapsis(el.A, el.E)/1000))
+		require.Equal(t, fmt.Sprintf("%.9f", leo.Epoch+600.0/86400), fmt.Sprintf("%.9f", el.Epoch))
+	})
+	t.Run("succeed in changing inclination with a normal burn", func(t *testing.T) {
+		di := gravity.Radians(10)
+		v := math.Sqrt(leo.Mu / leo.A)
+		// burn at the descending node, where the argument of latitude is Pi,
+		// pushing towards north to reduce the inclination.
+		tn := gravity.TimeOfFlight(leo.A, leo.E, 0, math.Pi-leo.W, leo.Mu)
+		m := maneuver.Maneuver{T: tn, DV: f64.Vec3{-v * (1 - math.Cos(di)), v * math.Sin(di), 0}, Frame: maneuver.VNB}
+		el := m.ApplyElements(leo)
+		require.Equal(t, fmt.Sprintf("%.3f", gravity.Degrees(leo.I-di)), fmt.Sprintf("%.3f", gravity.Degrees(el.I)))
+		require.Equal(t, fmt.Sprintf("%.0f", leo.A/1000), fmt.Sprintf("%.0f", el.A/1000))
+	})
+}
+
+func TestPlan(t *testing.T) {
+	t.Run("succeed in executing a hohmann transfer", func(t *testing.T) {
+		r2 := 42164e3
+		dv1, dv2, tof := gravity.Hohmann(leo.A, r2, leo.Mu)
+		plan := maneuver.Plan{maneuver.Prograde(1000, dv1), maneuver.Prograde(1000+tof, dv2)}
+		require.Equal(t, dv1+dv2, plan.DeltaV())
+
+		vehicle := &maneuver.Vehicle{Isp: 320, Dry: 1000, Propellant: 3000}
+		out, err := plan.Execute(leo, vehicle)
+		require.NoError(t, err)
+		require.Len(t, out, 2)
+		require.Equal(t, "42164", fmt.Sprintf("%.0f", out[1].A/1000))
+		require.Less(t, out[1].E, 1e-4)
+		require.Equal(t, fmt.Sprintf("%.6f", leo.Epoch+(1000+tof)/86400), fmt.Sprintf("%.6f", out[1].Epoch))
+		require.Equal(t, fmt.Sprintf("%.3f", maneuver.Propellant(320, 4000, dv1+dv2)), fmt.Sprintf("%.3f", vehicle.Consumed))
+	})
+	t.Run("fail when the vehicle runs out of propellant", func(t *testing.T) {
+		plan := maneuver.Plan{maneuver.Prograde(0, 500), maneuver.Prograde(100, 5000)}
+		vehicle := &maneuver.Vehicle{Isp: 300, Dry: 1000, Propellant: 500}
+		out, err := plan.Execute(leo, vehicle)
+		require.ErrorIs(t, err, maneuver.ErrPropellant)
+		require.Len(t, out, 1)
+	})
+}
+
+func TestRocket(t *testing.T) {
+	t.Run("succeed in calculating delta-v from a mass ratio", func(t *testing.T) {
+		require.Equal(t, "2039.24", fmt.Sprintf("%.2f", maneuver.DeltaV(300, 1000, 500)))
+	})
+	t.Run("succeed in inverting delta-v", func(t *testing.T) {
+		p := maneuver.Propellant(300, 1000, maneuver.DeltaV(300, 1000, 500))
+		require.Equal(t, "500.000", fmt.Sprintf("%.3f", p))
+	})
+	t.Run("succeed in tracking propellant across burns", func(t *testing.T) {
+		v := maneuver.Vehicle{Isp: 300, Dry: 500, Propellant: 500}
+		require.Equal(t, "2039.24", fmt.Sprintf("%.2f", v.DeltaV()))
+		p1, err := v.Burn(1000)
+		require.NoError(t, err)
+		p2, err := v.Burn(1000)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("%.6f", p1+p2), fmt.Sprintf("%.6f", v.Consumed))
+		require.Equal(t, fmt.Sprintf("%.6f", 500-p1-p2), fmt.Sprintf("%.6f", v.Propellant))
+		require.Equal(t, "39.24", fmt.Sprintf("%.2f", v.DeltaV()))
+	})
+	t.Run("fail to burn more than the remaining delta-v", func(t *testing.T) {
+		v := maneuver.Vehicle{Isp: 300, Dry: 500, Propellant: 500}
+		_, err := v.Burn(2100)
+		require.ErrorIs(t, err, maneuver.ErrPropellant)
+		require.Equal(t, 500.0, v.Propellant)
+	})
+}
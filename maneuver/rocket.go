@@ -0,0 +1,69 @@
+package maneuver
+
+import (
+	"errors"
+	"math"
+)
+
+// G0 standard gravity used to define specific impulse (m/s^2).
+const G0 float64 = 9.80665
+
+var ErrPropellant = errors.New("maneuver: not enough propellant")
+
+// DeltaV (m/s) from the Tsiolkovsky rocket equation.
+//
+// isp: specific impulse (s),
+// m0:  initial mass     (kg),
+// m1:  final mass       (kg).
+//
+// https://en.wikipedia.org/wiki/Tsiolkovsky_rocket_equation
+func DeltaV(isp, m0, m1 float64) float64 {
+	return isp * G0 * math.Log(m0/m1)
+}
+
+// Propellant mass (kg) burned to change velocity by dv.
+//
+// isp: specific impulse (s),
+// m0:  initial mass     (kg),
+// dv:  delta-v          (m/s).
+//
+// See DeltaV for more details.
+func Propellant(isp, m0, dv float64) float64 {
+	return m0 * -math.Expm1(-dv/(isp*G0))
+}
+
+// Vehicle mass budget.
+//
+// Isp:        specific impulse of the engine (s),
+// Dry:        mass without propellant        (kg),
+// Propellant: propellant remaining           (kg),
+// Consumed:   propellant burned so far       (kg).
+type Vehicle struct {
+	Isp        float64
+	Dry        float64
+	Propellant float64
+	Consumed   float64
+}
+
+// Mass of the vehicle including remaining propellant (kg).
+func (v Vehicle) Mass() float64 {
+	return v.Dry + v.Propellant
+}
+
+// DeltaV remaining (m/s).
+func (v Vehicle) DeltaV() float64 {
+	return DeltaV(v.Isp, v.Mass(), v.Dry)
+}
+
+// Burn propellant to change velocity by dv (m/s) and return the mass
+// burned (kg). Nothing is burned and ErrPropellant is returned if the
+// remaining propellant is not enough.
+func (v *Vehicle) Burn(dv float64) (float64, error) {
+	p := Propellant(v.Isp, v.Mass(), dv)
+	if p > v.Propellant {
+		return 0, ErrPropellant
+	}
+	v.Propellant -= p
+	v.Consumed += p
+	return p, nil
+}
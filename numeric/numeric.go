@@ -0,0 +1,192 @@
+// Package numeric propagates spacecraft by numerically integrating the
+// accelerations of a set of force models with a fixed step fourth order
+// Runge-Kutta integrator. Unlike the two body functions of the gravity
+// package it can model forces such as thrust which change the orbit and
+// the mass of the spacecraft over time.
+package numeric
+
+import (
+	"math"
+	"sort"
+
+	"github.com/wafer-bw/gorbit/ephemeris"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+// State of a spacecraft.
+//
+// T:    time since epoch                  (s),
+// R:    position relative to primary body (m),
+// V:    velocity relative to primary body (m/s),
+// Mass: mass of the spacecraft            (kg).
+type State struct {
+	T    float64
+	R    f64.Vec3
+	V    f64.Vec3
+	Mass float64
+}
+
+// ForceModel contributing to the motion of a spacecraft.
+type ForceModel interface {
+	// Acceleration (m/s^2) and rate of change of mass (kg/s) at s.
+	Acceleration(s State) (f64.Vec3, float64)
+}
+
+// Switcher is implemented by force models which switch on or off at
+// known times. The propagator ends a step at each of them so that the
+// discontinuities do not degrade the accuracy of the integrator.
+type Switcher interface {
+	// Switches times since epoch (s).
+	Switches() []float64
+}
+
+// PointMass gravity of the primary body.
+//
+// Mu: standard gravitational parameter (m^3/s^2).
+type PointMass struct {
+	Mu float64
+}
+
+// Acceleration due to the gravity of the primary body.
+func (p PointMass) Acceleration(s State) (f64.Vec3, float64) {
+	r := vec3.Magnitude(s.R)
+	return vec3.MulScalar(s.R, -p.Mu/(r*r*r)), 0
+}
+
+// Propagator integrating the sum of Models with steps of at most Step
+// seconds where Step > 0.
+type Propagator struct {
+	Models []ForceModel
+	Step   float64
+}
+
+// Propagate s to t seconds since epoch, which may be before s.T. It
+// panics unless Step > 0.
+func (p Propagator) Propagate(s State, t float64) State {
+	p.check()
+	for _, stop := range p.stops(s.T, t) {
+		s = p.segment(s, stop)
+	}
+	return s
+}
+
+// Table of states from s to t seconds since epoch every interval seconds
+// which may be interpolated and searched for events. It panics unless
+// Step > 0 and interval > 0. See ephemeris.Table for more details.
+func (p Propagator) Table(s State, t, interval float64) ephemeris.Table {
+	p.check()
+	if !(interval > 0) {
+		panic("numeric: Table interval must be greater than 0")
+	}
+	tb := ephemeris.Table{{T: s.T, R: s.R, V: s.V}}
+	n := int(math.Ceil((t - s.T) / interval))
+	t0 := s.T
+	for k := 1; k <= n; k++ {
+		s = p.Propagate(s, math.Min(t0+float64(k)*interval, t))
+		tb = append(tb, ephemeris.Entry{T: s.T, R: s.R, V: s.V})
+	}
+	return tb
+}
+
+func (p Propagator) check() {
+	if !(p.Step > 0) {
+		panic("numeric: Propagator.Step must be greater than 0")
+	}
+}
+
+// stops between t0 and t1 in the direction of travel ending with t1.
+func (p Propagator) stops(t0, t1 float64) []float64 {
+	stops := []float64{}
+	for _, m := range p.Models {
+		sw, ok := m.(Switcher)
+		if !ok {
+			continue
+		}
+		for _, t := range sw.Switches() {
+			if (t > t0 && t < t1) || (t < t0 && t > t1) {
+				stops = append(stops, t)
+			}
+		}
+	}
+	sort.Float64s(stops)
+	if t1 < t0 {
+		sort.Sort(sort.Reverse(sort.Float64Slice(stops)))
+	}
+	return append(stops, t1)
+}
+
+// segment integrates s to t without any switches in between using steps
+// of equal length. Models are evaluated at times just inside the segment
+// so that a model switching at either end is seen consistently by every
+// stage of the integrator.
+func (p Propagator) segment(s State, t float64) State {
+	n := math.Ceil(math.Abs(t-s.T) / p.Step)
+	if n < 1 {
+		return s
+	}
+	h := (t - s.T) / n
+	t0 := s.T
+	eps := 1e-9 * (t - t0)
+	in := interval{math.Min(t0+eps, t-eps), math.Max(t0+eps, t-eps)}
+	for k := 1; k <= int(n); k++ {
+		s = p.rk4(s, h, in)
+		s.T = t0 + float64(k)*h
+	}
+	return s
+}
+
+// interval of times models are evaluated at.
+type interval struct {
+	lo, hi float64
+}
+
+// derivative of position, velocity and mass.
+type derivative struct {
+	r, v f64.Vec3
+	m    float64
+}
+
+func (p Propagator) derivative(s State, in interval) derivative {
+	s.T = math.Max(in.lo, math.Min(in.hi, s.T))
+	d := derivative{r: s.V}
+	for _, model := range p.Models {
+		a, mdot := model.Acceleration(s)
+		d.v = vec3.Add(d.v, a)
+		d.m += mdot
+	}
+	return d
+}
+
+func (s State) step(d derivative, h float64) State {
+	return State{
+		T:    s.T + h,
+		R:    vec3.Add(s.R, vec3.MulScalar(d.r, h)),
+		V:    vec3.Add(s.V, vec3.MulScalar(d.v, h)),
+		Mass: s.Mass + d.m*h,
+	}
+}
+
+// rk4 step of h seconds.
+//
+// https://en.wikipedia.org/wiki/Runge%E2%80%93Kutta_methods
+func (p Propagator) rk4(s State, h float64, in interval) State {
+	k1 := p.derivative(s, in)
+	k2 := p.derivative(s.step(k1, h/2), in)
+	k3 := p.derivative(s.step(k2, h/2), in)
+	k4 := p.derivative(s.step(k3, h), in)
+	return s.step(derivative{
+		r: sum(k1.r, k2.r, k3.r, k4.r),
+		v: sum(k1.v, k2.v, k3.v, k4.v),
+		m: (k1.m + 2*k2.m + 2*k3.m + k4.m) / 6,
+	}, h)
+}
+
+// sum weighted by the Runge-Kutta coefficients 1/6, 1/3, 1/3 and 1/6.
+func sum(k1, k2, k3, k4 f64.Vec3) f64.Vec3 {
+	return f64.Vec3{
+		(k1[0] + 2*k2[0] + 2*k3[0] + k4[0]) / 6,
+		(k1[1] + 2*k2[1] + 2*k3[1] + k4[1]) / 6,
+		(k1[2] + 2*k2[2] + 2*k3[2] + k4[2]) / 6,
+	}
+}
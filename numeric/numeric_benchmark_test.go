@@ -0,0 +1,15 @@
+package numeric_test
+
+import (
+	"testing"
+
+	"github.com/wafer-bw/gorbit/numeric"
+)
+
+func BenchmarkPropagate(b *testing.B) {
+	th := numeric.Thrust{Force: 100, Isp: 300, Start: 0, Stop: 600, Direction: numeric.Prograde}
+	p := numeric.Propagator{Models: []numeric.ForceModel{numeric.PointMass{Mu: mu}, th}, Step: 10}
+	for i := 0; i < b.N; i++ {
+		p.Propagate(leo, 6000)
+	}
+}
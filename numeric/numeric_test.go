@@ -0,0 +1,106 @@
+package numeric_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/maneuver"
+	"github.com/wafer-bw/gorbit/numeric"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+var (
+	mu  = bodies.Earth.GM
+	leo = numeric.State{R: f64.Vec3{7000e3, 0, 0}, V: f64.Vec3{0, math.Sqrt(mu / 7000e3), 0}, Mass: 1000}
+)
+
+func energy(s numeric.State) float64 {
+	return vec3.Dot(s.V, s.V)/2 - mu/vec3.Magnitude(s.R)
+}
+
+func TestPropagate(t *testing.T) {
+	t.Run("succeed in matching two body propagation", func(t *testing.T) {
+		s := numeric.State{R: f64.Vec3{7000e3, 0, 100e3}, V: f64.Vec3{0, 8000, 500}, Mass: 1}
+		p := numeric.Propagator{Models: []numeric.ForceModel{numeric.PointMass{Mu: mu}}, Step: 10}
+		got := p.Propagate(s, 10000)
+		wantR, wantV := gravity.Propagate(s.R, s.V, 10000, mu)
+		require.Equal(t, 10000.0, got.T)
+		require.Less(t, vec3.Magnitude(vec3.Sub(got.R, wantR)), 1.0)
+		require.Less(t, vec3.Magnitude(vec3.Sub(got.V, wantV)), 1e-3)
+		require.Equal(t, 1.0, got.Mass)
+	})
+	t.Run("succeed in propagating backwards", func(t *testing.T) {
+		p := numeric.Propagator{Models: []numeric.ForceModel{numeric.PointMass{Mu: mu}}, Step: 10}
+		back := p.Propagate(p.Propagate(leo, 3000), 0)
+		require.Less(t, vec3.Magnitude(vec3.Sub(back.R, leo.R)), 1e-3)
+	})
+	t.Run("succeed in tabulating states", func(t *testing.T) {
+		p := numeric.Propagator{Models: []numeric.ForceModel{numeric.PointMass{Mu: mu}}, Step: 10}
+		tb := p.Table(leo, 1000, 300)
+		require.Len(t, tb, 5)
+		require.Equal(t, 0.0, tb[0].T)
+		require.Equal(t, 900.0, tb[3].T)
+		require.Equal(t, 1000.0, tb[4].T)
+	})
+	t.Run("fail to propagate with a non positive step", func(t *testing.T) {
+		for _, step := range []float64{0, -10, math.NaN()} {
+			p := numeric.Propagator{Models: []numeric.ForceModel{numeric.PointMass{Mu: mu}}, Step: step}
+			require.Panics(t, func() { p.Propagate(leo, 1000) })
+			require.Panics(t, func() { p.Table(leo, 1000, 300) })
+		}
+	})
+	t.Run("fail to tabulate with a non positive interval", func(t *testing.T) {
+		p := numeric.Propagator{Models: []numeric.ForceModel{numeric.PointMass{Mu: mu}}, Step: 10}
+		require.Panics(t, func() { p.Table(leo, 1000, 0) })
+		require.Panics(t, func() { p.Table(leo, 1000, -300) })
+	})
+}
+
+func TestThrust(t *testing.T) {
+	t.Run("succeed in matching the rocket equation in free space", func(t *testing.T) {
+		th := numeric.Thrust{Force: 500, Isp: 300, Start: 5.5, Stop: 605.5, Direction: numeric.Fixed(f64.Vec3{0, 0, 2})}
+		p := numeric.Propagator{Models: []numeric.ForceModel{th}, Step: 10}
+		s := p.Propagate(numeric.State{Mass: 1000}, 1000)
+		used := th.MassFlow() * 600
+		require.Equal(t, fmt.Sprintf("%.9f", 1000-used), fmt.Sprintf("%.9f", s.Mass))
+		require.Equal(t, fmt.Sprintf("%.6f", maneuver.DeltaV(300, 1000, 1000-used)), fmt.Sprintf("%.6f", s.V[2]))
+		require.Equal(t, "0.000000", fmt.Sprintf("%.6f", s.V[0]))
+	})
+	t.Run("succeed in capturing gravity losses of a long prograde burn", func(t *testing.T) {
+		th := numeric.Thrust{Force: 100, Isp: 300, Start: 0, Stop: 3000, Direction: numeric.Prograde}
+		p := numeric.Propagator{Models: []numeric.ForceModel{numeric.PointMass{Mu: mu}, th}, Step: 5}
+		s := p.Propagate(leo, 3000)
+
+		dv := maneuver.DeltaV(300, leo.Mass, s.Mass)
+		impulsive := leo
+		impulsive.V = vec3.Add(impulsive.V, vec3.MulScalar(vec3.Normalize(leo.V), dv))
+		require.Greater(t, energy(s), energy(leo))
+		require.Less(t, energy(s), energy(impulsive))
+		require.Greater(t, energy(s)-energy(leo), 0.9*(energy(impulsive)-energy(leo)))
+	})
+	t.Run("succeed in lowering the orbit with a retrograde burn", func(t *testing.T) {
+		th := numeric.Thrust{Force: 100, Isp: 300, Start: 0, Stop: 600, Direction: numeric.Retrograde}
+		p := numeric.Propagator{Models: []numeric.ForceModel{numeric.PointMass{Mu: mu}, th}, Step: 5}
+		require.Less(t, energy(p.Propagate(leo, 600)), energy(leo))
+	})
+	t.Run("succeed in steering with a custom direction law", func(t *testing.T) {
+		normal := func(s numeric.State) f64.Vec3 { return vec3.Normalize(vec3.Cross(s.R, s.V)) }
+		th := numeric.Thrust{Force: 100, Isp: 300, Start: 0, Stop: 600, Direction: normal}
+		p := numeric.Propagator{Models: []numeric.ForceModel{numeric.PointMass{Mu: mu}, th}, Step: 5}
+		s := p.Propagate(leo, 600)
+		_, _, _, _, i, _ := gravity.OrbitalElementsMu(s.R, s.V, mu)
+		require.Greater(t, gravity.Degrees(i), 0.1)
+		require.Equal(t, fmt.Sprintf("%.0f", energy(leo)), fmt.Sprintf("%.0f", energy(s)))
+	})
+	t.Run("succeed in coasting outside the burn", func(t *testing.T) {
+		th := numeric.Thrust{Force: 100, Isp: 300, Start: 100, Stop: 200, Direction: numeric.Prograde}
+		a, mdot := th.Acceleration(numeric.State{T: 200, V: f64.Vec3{1, 0, 0}, Mass: 1})
+		require.Equal(t, f64.Vec3{}, a)
+		require.Equal(t, 0.0, mdot)
+	})
+}
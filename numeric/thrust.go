@@ -0,0 +1,64 @@
+package numeric
+
+import (
+	"github.com/wafer-bw/gorbit/maneuver"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+// Direction law returning the unit thrust direction in the inertial frame
+// at a state.
+type Direction func(s State) f64.Vec3
+
+// Fixed inertial thrust direction.
+func Fixed(d f64.Vec3) Direction {
+	d = vec3.Normalize(d)
+	return func(State) f64.Vec3 { return d }
+}
+
+// Prograde thrust along the velocity.
+func Prograde(s State) f64.Vec3 {
+	return vec3.Normalize(s.V)
+}
+
+// Retrograde thrust against the velocity.
+func Retrograde(s State) f64.Vec3 {
+	return vec3.MulScalar(vec3.Normalize(s.V), -1)
+}
+
+// Thrust of an engine burning from Start to Stop.
+//
+// Force:     thrust                          (N),
+// Isp:       specific impulse                (s),
+// Start:     time since epoch of ignition    (s),
+// Stop:      time since epoch of cutoff      (s),
+// Direction: direction law of the thrust.
+//
+// Mass flows at Force / (Isp * G0) while burning. The burn is not cut
+// short when propellant runs out so check the mass after propagating or
+// size the burn with maneuver.Propellant.
+type Thrust struct {
+	Force     float64
+	Isp       float64
+	Start     float64
+	Stop      float64
+	Direction Direction
+}
+
+// Acceleration due to thrust and the rate the propellant is burned.
+func (th Thrust) Acceleration(s State) (f64.Vec3, float64) {
+	if s.T < th.Start || s.T >= th.Stop {
+		return f64.Vec3{}, 0
+	}
+	return vec3.MulScalar(th.Direction(s), th.Force/s.Mass), -th.MassFlow()
+}
+
+// MassFlow rate of the burning engine (kg/s).
+func (th Thrust) MassFlow() float64 {
+	return th.Force / (th.Isp * maneuver.G0)
+}
+
+// Switches at ignition and cutoff.
+func (th Thrust) Switches() []float64 {
+	return []float64{th.Start, th.Stop}
+}
@@ -0,0 +1,362 @@
+package odm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+// ReadOPM in KVN form.
+func ReadOPM(r io.Reader) (OPM, error) {
+	o := OPM{}
+	lines, err := readLines(r)
+	if err != nil {
+		return o, err
+	}
+	if len(lines) == 0 || lines[0].key != "CCSDS_OPM_VERS" {
+		return o, fmt.Errorf("%w: expected CCSDS_OPM_VERS", ErrVersion)
+	}
+
+	b := block{values: map[string]string{}}
+	stage := 0
+	for _, l := range lines[1:] {
+		switch {
+		case l.key == "COMMENT":
+			if stage == 0 {
+				o.Header.Comments = append(o.Header.Comments, l.value)
+			} else if stage == 1 {
+				o.Metadata.Comments = append(o.Metadata.Comments, l.value)
+			}
+		case l.key == "":
+			return o, fmt.Errorf("%w: line %d", ErrStructure, l.n)
+		default:
+			switch l.key {
+			case "CREATION_DATE", "ORIGINATOR", "MESSAGE_ID":
+				stage = 1
+			default:
+				stage = 2
+			}
+			b.values[l.key] = l.value
+		}
+	}
+
+	o.Header.Version = lines[0].value
+	o.Header.CreationDate = b.time("CREATION_DATE")
+	o.Header.Originator = b.string("ORIGINATOR")
+	o.Metadata = b.metadata(o.Metadata.Comments)
+	o.State.Epoch = b.time("EPOCH")
+	o.State.R = b.vec3("X", "Y", "Z", 1000)
+	o.State.V = b.vec3("X_DOT", "Y_DOT", "Z_DOT", 1000)
+	if _, ok := b.values["SEMI_MAJOR_AXIS"]; ok {
+		k := Keplerian{}
+		k.A = b.float("SEMI_MAJOR_AXIS", 1000)
+		k.E = b.float("ECCENTRICITY", 1)
+		k.I = gravity.Radians(b.float("INCLINATION", 1))
+		k.LAN = gravity.Radians(b.float("RA_OF_ASC_NODE", 1))
+		k.W = gravity.Radians(b.float("ARG_OF_PERICENTER", 1))
+		k.Mu = b.float("GM", 1e9)
+		if _, ok := b.values["TRUE_ANOMALY"]; ok {
+			k.M = meanAnomaly(k.E, gravity.Radians(b.float("TRUE_ANOMALY", 1)))
+		} else {
+			k.M = gravity.Radians(b.float("MEAN_ANOMALY", 1))
+		}
+		o.Keplerian = &k
+	}
+	return o, b.err
+}
+
+// WriteOPM in KVN form.
+func WriteOPM(w io.Writer, o OPM) error {
+	bw := bufio.NewWriter(w)
+	kv(bw, "CCSDS_OPM_VERS", version(o.Header.Version))
+	writeHeader(bw, o.Header)
+	fmt.Fprintln(bw)
+	writeMetadata(bw, o.Metadata)
+	fmt.Fprintln(bw)
+	kv(bw, "EPOCH", formatTime(o.State.Epoch))
+	for i, key := range []string{"X", "Y", "Z"} {
+		kvu(bw, key, o.State.R[i]/1000, "km")
+	}
+	for i, key := range []string{"X_DOT", "Y_DOT", "Z_DOT"} {
+		kvu(bw, key, o.State.V[i]/1000, "km/s")
+	}
+	if k := o.Keplerian; k != nil {
+		fmt.Fprintln(bw)
+		kvu(bw, "SEMI_MAJOR_AXIS", k.A/1000, "km")
+		kv(bw, "ECCENTRICITY", formatFloat(k.E))
+		kvu(bw, "INCLINATION", gravity.Degrees(k.I), "deg")
+		kvu(bw, "RA_OF_ASC_NODE", gravity.Degrees(k.LAN), "deg")
+		kvu(bw, "ARG_OF_PERICENTER", gravity.Degrees(k.W), "deg")
+		kvu(bw, "MEAN_ANOMALY", gravity.Degrees(k.M), "deg")
+		kvu(bw, "GM", k.Mu/1e9, "km**3/s**2")
+	}
+	return bw.Flush()
+}
+
+// ReadOEM in KVN form.
+func ReadOEM(r io.Reader) (OEM, error) {
+	o := OEM{}
+	lines, err := readLines(r)
+	if err != nil {
+		return o, err
+	}
+	if len(lines) == 0 || lines[0].key != "CCSDS_OEM_VERS" {
+		return o, fmt.Errorf("%w: expected CCSDS_OEM_VERS", ErrVersion)
+	}
+	o.Header.Version = lines[0].value
+
+	header := block{values: map[string]string{}}
+	var meta *block
+	var seg *Segment
+	covariance := false
+	for _, l := range lines[1:] {
+		switch {
+		case covariance:
+			covariance = l.key != "COVARIANCE_STOP"
+		case l.key == "COVARIANCE_START":
+			covariance = true
+		case l.key == "META_START":
+			o.Segments = append(o.Segments, Segment{})
+			seg = &o.Segments[len(o.Segments)-1]
+			meta = &block{values: map[string]string{}}
+		case l.key == "META_STOP":
+			if meta == nil {
+				return o, fmt.Errorf("%w: META_STOP without META_START on line %d", ErrStructure, l.n)
+			}
+			seg.Metadata = meta.metadata(seg.Metadata.Comments)
+			seg.StartTime = meta.time("START_TIME")
+			seg.StopTime = meta.time("STOP_TIME")
+			seg.UseableStartTime = meta.optionalTime("USEABLE_START_TIME")
+			seg.UseableStopTime = meta.optionalTime("USEABLE_STOP_TIME")
+			seg.Interpolation = meta.optional("INTERPOLATION")
+			if meta.optional("INTERPOLATION_DEGREE") != "" {
+				seg.InterpolationDegree = int(meta.float("INTERPOLATION_DEGREE", 1))
+			}
+			if meta.err != nil {
+				return o, meta.err
+			}
+			meta = nil
+		case l.key == "COMMENT":
+			if seg == nil {
+				o.Header.Comments = append(o.Header.Comments, l.value)
+			} else if meta != nil {
+				seg.Metadata.Comments = append(seg.Metadata.Comments, l.value)
+			}
+		case seg == nil:
+			if l.key == "" {
+				return o, fmt.Errorf("%w: data before META_START on line %d", ErrStructure, l.n)
+			}
+			header.values[l.key] = l.value
+		case meta != nil:
+			meta.values[l.key] = l.value
+		case l.key == "":
+			s, err := parseState(l)
+			if err != nil {
+				return o, err
+			}
+			seg.States = append(seg.States, s)
+		default:
+			return o, fmt.Errorf("%w: unexpected keyword %s on line %d", ErrStructure, l.key, l.n)
+		}
+	}
+	if meta != nil {
+		return o, fmt.Errorf("%w: missing META_STOP", ErrStructure)
+	}
+
+	o.Header.CreationDate = header.time("CREATION_DATE")
+	o.Header.Originator = header.string("ORIGINATOR")
+	return o, header.err
+}
+
+// WriteOEM in KVN form.
+func WriteOEM(w io.Writer, o OEM) error {
+	bw := bufio.NewWriter(w)
+	kv(bw, "CCSDS_OEM_VERS", version(o.Header.Version))
+	writeHeader(bw, o.Header)
+	for _, seg := range o.Segments {
+		fmt.Fprintln(bw)
+		fmt.Fprintln(bw, "META_START")
+		writeMetadata(bw, seg.Metadata)
+		kv(bw, "START_TIME", formatTime(seg.StartTime))
+		if !seg.UseableStartTime.IsZero() {
+			kv(bw, "USEABLE_START_TIME", formatTime(seg.UseableStartTime))
+		}
+		if !seg.UseableStopTime.IsZero() {
+			kv(bw, "USEABLE_STOP_TIME", formatTime(seg.UseableStopTime))
+		}
+		kv(bw, "STOP_TIME", formatTime(seg.StopTime))
+		if seg.Interpolation != "" {
+			kv(bw, "INTERPOLATION", seg.Interpolation)
+			kv(bw, "INTERPOLATION_DEGREE", strconv.Itoa(seg.InterpolationDegree))
+		}
+		fmt.Fprintln(bw, "META_STOP")
+		fmt.Fprintln(bw)
+		for _, s := range seg.States {
+			fmt.Fprintf(bw, "%s %s %s %s %s %s %s\n", formatTime(s.Epoch),
+				formatFloat(s.R[0]/1000), formatFloat(s.R[1]/1000), formatFloat(s.R[2]/1000),
+				formatFloat(s.V[0]/1000), formatFloat(s.V[1]/1000), formatFloat(s.V[2]/1000))
+		}
+	}
+	return bw.Flush()
+}
+
+// line of a KVN message. Data lines have an empty key and the whole line
+// as the value.
+type line struct {
+	n     int
+	key   string
+	value string
+}
+
+// readLines of a KVN message skipping blank lines and stripping units.
+func readLines(r io.Reader) ([]line, error) {
+	lines := []line{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "":
+		case text == "COMMENT" || strings.HasPrefix(text, "COMMENT "):
+			lines = append(lines, line{n: n, key: "COMMENT", value: strings.TrimSpace(text[len("COMMENT"):])})
+		case strings.HasSuffix(text, "_START") || strings.HasSuffix(text, "_STOP"):
+			if !strings.Contains(text, "=") {
+				lines = append(lines, line{n: n, key: text})
+				break
+			}
+			fallthrough
+		default:
+			key, value, ok := strings.Cut(text, "=")
+			if !ok {
+				lines = append(lines, line{n: n, value: text})
+				break
+			}
+			value = strings.TrimSpace(value)
+			if i := strings.Index(value, "["); i >= 0 && strings.HasSuffix(value, "]") {
+				value = strings.TrimSpace(value[:i])
+			}
+			lines = append(lines, line{n: n, key: strings.TrimSpace(key), value: value})
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func parseState(l line) (State, error) {
+	s := State{}
+	fields := strings.Fields(l.value)
+	if len(fields) != 7 && len(fields) != 10 {
+		return s, fmt.Errorf("%w: expected 7 or 10 fields on line %d", ErrValue, l.n)
+	}
+	var err error
+	if s.Epoch, err = parseTime(fields[0]); err != nil {
+		return s, fmt.Errorf("%w on line %d", err, l.n)
+	}
+	for i := 0; i < 6; i++ {
+		f, err := strconv.ParseFloat(fields[i+1], 64)
+		if err != nil {
+			return s, fmt.Errorf("%w: %q on line %d", ErrValue, fields[i+1], l.n)
+		}
+		if i < 3 {
+			s.R[i] = f * 1000
+		} else {
+			s.V[i-3] = f * 1000
+		}
+	}
+	return s, nil
+}
+
+// block of keyword values which records the first error encountered.
+type block struct {
+	values map[string]string
+	err    error
+}
+
+func (b *block) optional(key string) string {
+	return b.values[key]
+}
+
+func (b *block) string(key string) string {
+	v, ok := b.values[key]
+	if !ok && b.err == nil {
+		b.err = fmt.Errorf("%w: %s", ErrKeyword, key)
+	}
+	return v
+}
+
+func (b *block) float(key string, scale float64) float64 {
+	s := b.string(key)
+	if s == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && b.err == nil {
+		b.err = fmt.Errorf("%w: %s = %q", ErrValue, key, s)
+	}
+	return f * scale
+}
+
+func (b *block) vec3(x, y, z string, scale float64) f64.Vec3 {
+	return f64.Vec3{b.float(x, scale), b.float(y, scale), b.float(z, scale)}
+}
+
+func (b *block) time(key string) time.Time {
+	s := b.string(key)
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := parseTime(s)
+	if err != nil && b.err == nil {
+		b.err = fmt.Errorf("%w: %s", err, key)
+	}
+	return t
+}
+
+func (b *block) optionalTime(key string) time.Time {
+	if b.optional(key) == "" {
+		return time.Time{}
+	}
+	return b.time(key)
+}
+
+func (b *block) metadata(comments []string) Metadata {
+	return Metadata{
+		ObjectName: b.string("OBJECT_NAME"),
+		ObjectID:   b.string("OBJECT_ID"),
+		CenterName: b.string("CENTER_NAME"),
+		RefFrame:   b.string("REF_FRAME"),
+		TimeSystem: b.string("TIME_SYSTEM"),
+		Comments:   comments,
+	}
+}
+
+func kv(w io.Writer, key, value string) {
+	fmt.Fprintf(w, "%-20s = %s\n", key, value)
+}
+
+func kvu(w io.Writer, key string, value float64, units string) {
+	fmt.Fprintf(w, "%-20s = %s [%s]\n", key, formatFloat(value), units)
+}
+
+func writeHeader(w io.Writer, h Header) {
+	for _, c := range h.Comments {
+		fmt.Fprintf(w, "COMMENT %s\n", c)
+	}
+	kv(w, "CREATION_DATE", formatTime(h.CreationDate))
+	kv(w, "ORIGINATOR", h.Originator)
+}
+
+func writeMetadata(w io.Writer, m Metadata) {
+	for _, c := range m.Comments {
+		fmt.Fprintf(w, "COMMENT %s\n", c)
+	}
+	kv(w, "OBJECT_NAME", m.ObjectName)
+	kv(w, "OBJECT_ID", m.ObjectID)
+	kv(w, "CENTER_NAME", m.CenterName)
+	kv(w, "REF_FRAME", m.RefFrame)
+	kv(w, "TIME_SYSTEM", m.TimeSystem)
+}
@@ -0,0 +1,214 @@
+// Package odm reads and writes CCSDS Orbit Data Messages.
+//
+// Orbit Parameter Messages (OPM) hold a single state vector and optionally
+// the osculating Keplerian elements at the same epoch. Orbit Ephemeris
+// Messages (OEM) hold one or more segments of time tagged state vectors.
+// Both are supported in the Keyword Value Notation (KVN) and XML forms.
+//
+// Messages use kilometers and degrees on the wire. Values are converted to
+// the units of the gravity package (m, m/s, rad, m^3/s^2) when read and back
+// when written. Spacecraft parameters, covariance, maneuvers and user
+// defined parameters are not supported and are skipped when read.
+//
+// https://public.ccsds.org/Pubs/502x0b3e1.pdf
+package odm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+var (
+	ErrVersion   = errors.New("odm: missing or unexpected version")
+	ErrKeyword   = errors.New("odm: missing keyword")
+	ErrValue     = errors.New("odm: malformed value")
+	ErrStructure = errors.New("odm: unexpected structure")
+)
+
+// Version of the messages written.
+const Version = "2.0"
+
+// timeLayout used when writing epochs.
+const timeLayout = "2006-01-02T15:04:05.000000"
+
+// Header common to all messages.
+//
+// Version:      message format version, Version if empty when written,
+// CreationDate: date the message was created,
+// Originator:   creating agency or operator,
+// Comments:     header comments.
+type Header struct {
+	Version      string
+	CreationDate time.Time
+	Originator   string
+	Comments     []string
+}
+
+// Metadata describing the object and reference frame of the data.
+//
+// ObjectName: spacecraft name,
+// ObjectID:   international designator,
+// CenterName: origin of the reference frame such as EARTH or SUN,
+// RefFrame:   reference frame such as EME2000, ICRF or TEME,
+// TimeSystem: time system of all epochs such as UTC or TDB,
+// Comments:   metadata comments.
+//
+// Epochs are stored as time.Time values in the UTC location regardless of
+// TimeSystem. Use the epoch package to convert between time systems.
+type Metadata struct {
+	ObjectName string
+	ObjectID   string
+	CenterName string
+	RefFrame   string
+	TimeSystem string
+	Comments   []string
+}
+
+// State vector at an epoch.
+//
+// Epoch: time tag,
+// R:     position relative to the center (m),
+// V:     velocity relative to the center (m/s).
+type State struct {
+	Epoch time.Time
+	R     f64.Vec3
+	V     f64.Vec3
+}
+
+// Keplerian osculating elements using the conventions of
+// gravity.OrbitalElements.
+//
+// A:   semi-major axis                  (m),
+// E:   eccentricity                     (0-1),
+// W:   argument of periapsis            (rad),
+// LAN: longitude of ascending node      (rad),
+// I:   inclination                      (rad),
+// M:   mean anomaly                     (rad),
+// Mu:  standard gravitational parameter (m^3/s^2).
+//
+// Messages may give either the true or mean anomaly. True anomalies are
+// converted to mean anomalies when read.
+type Keplerian struct {
+	A   float64
+	E   float64
+	W   float64
+	LAN float64
+	I   float64
+	M   float64
+	Mu  float64
+}
+
+// KeplerianElements from Cartesian state vectors. See
+// gravity.OrbitalElementsMu for more details.
+func KeplerianElements(r, v f64.Vec3, mu float64) Keplerian {
+	k := Keplerian{Mu: mu}
+	k.A, k.E, k.W, k.LAN, k.I, k.M = gravity.OrbitalElementsMu(r, v, mu)
+	return k
+}
+
+// StateVectors at the epoch of the elements. See gravity.StateVectorsMu for
+// more details.
+func (k Keplerian) StateVectors() (f64.Vec3, f64.Vec3) {
+	return gravity.StateVectorsMu(k.A, k.E, k.W, k.LAN, k.I, k.M, 0, k.Mu)
+}
+
+// OPM Orbit Parameter Message.
+//
+// Keplerian is nil when the message has no Keplerian elements block.
+type OPM struct {
+	Header
+	Metadata
+	State
+	Keplerian *Keplerian
+}
+
+// OEM Orbit Ephemeris Message.
+type OEM struct {
+	Header
+	Segments []Segment
+}
+
+// Segment of an OEM.
+//
+// Metadata:            object and reference frame of the states,
+// StartTime:           start of the time span covered by the states,
+// StopTime:            end of the time span covered by the states,
+// UseableStartTime:    start of the recommended span, zero if absent,
+// UseableStopTime:     end of the recommended span, zero if absent,
+// Interpolation:       recommended method such as HERMITE or LAGRANGE,
+// InterpolationDegree: recommended interpolation degree,
+// States:              state vectors in increasing epoch order.
+//
+// Accelerations in the data lines are ignored when read.
+type Segment struct {
+	Metadata
+	StartTime           time.Time
+	StopTime            time.Time
+	UseableStartTime    time.Time
+	UseableStopTime     time.Time
+	Interpolation       string
+	InterpolationDegree int
+	States              []State
+}
+
+// meanAnomaly (rad) from the true anomaly ta (rad) for elliptic and
+// hyperbolic orbits.
+func meanAnomaly(e, ta float64) float64 {
+	if e < 1 {
+		eca := 2 * math.Atan(math.Sqrt((1-e)/(1+e))*math.Tan(ta/2))
+		m := eca - e*math.Sin(eca)
+		if m < 0 {
+			m += 2 * math.Pi
+		}
+		return m
+	}
+	h := 2 * math.Atanh(math.Sqrt((e-1)/(e+1))*math.Tan(ta/2))
+	return e*math.Sinh(h) - h
+}
+
+// parseTime in either the calendar (YYYY-MM-DDThh:mm:ss) or ordinal
+// (YYYY-DDDThh:mm:ss) CCSDS formats with optional fractional seconds and
+// an optional trailing Z.
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "Z")
+	date, clock, ok := strings.Cut(s, "T")
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: epoch %q", ErrValue, s)
+	}
+	if len(date) != 8 {
+		t, err := time.Parse("2006-01-02T15:04:05.999999999", s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: epoch %q", ErrValue, s)
+		}
+		return t, nil
+	}
+	year, err1 := strconv.Atoi(date[:4])
+	day, err2 := strconv.Atoi(date[5:])
+	t, err3 := time.Parse("15:04:05.999999999", clock)
+	if err1 != nil || err2 != nil || err3 != nil || date[4] != '-' {
+		return time.Time{}, fmt.Errorf("%w: epoch %q", ErrValue, s)
+	}
+	return time.Date(year, time.January, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC), nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', 16, 64)
+}
+
+func version(v string) string {
+	if v == "" {
+		return Version
+	}
+	return v
+}
@@ -0,0 +1,195 @@
+package odm_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/odm"
+	"github.com/wafer-bw/gorbit/vec3"
+)
+
+func readOPM(t *testing.T) odm.OPM {
+	t.Helper()
+	f, err := os.Open("testdata/opm.kvn")
+	require.NoError(t, err)
+	defer f.Close()
+	o, err := odm.ReadOPM(f)
+	require.NoError(t, err)
+	return o
+}
+
+func readOEM(t *testing.T) odm.OEM {
+	t.Helper()
+	f, err := os.Open("testdata/oem.kvn")
+	require.NoError(t, err)
+	defer f.Close()
+	o, err := odm.ReadOEM(f)
+	require.NoError(t, err)
+	return o
+}
+
+func TestReadOPM(t *testing.T) {
+	t.Run("succeed in reading a KVN orbit parameter message", func(t *testing.T) {
+		o := readOPM(t)
+		require.Equal(t, "2.0", o.Header.Version)
+		require.Equal(t, "GSOC", o.Header.Originator)
+		require.Len(t, o.Header.Comments, 2)
+		require.Equal(t, []string{"GEOCENTRIC, CARTESIAN, INERTIAL"}, o.Metadata.Comments)
+		require.Equal(t, "EUTELSAT W4", o.Metadata.ObjectName)
+		require.Equal(t, "EME2000", o.Metadata.RefFrame)
+		require.Equal(t, time.Date(2006, 6, 3, 0, 0, 0, 0, time.UTC), o.State.Epoch)
+		require.Equal(t, "[6655994.2 -40218575.1 -82917.7]", fmt.Sprintf("%.1f", o.State.R))
+		require.Equal(t, "[3115.48208 470.42605 -1.01495]", fmt.Sprintf("%.5f", o.State.V))
+	})
+	t.Run("succeed in converting the true anomaly to the mean anomaly", func(t *testing.T) {
+		o := readOPM(t)
+		require.NotNil(t, o.Keplerian)
+		require.Equal(t, "41399512.3", fmt.Sprintf("%.1f", o.Keplerian.A))
+		require.Equal(t, "3.986004415e+14", fmt.Sprintf("%.9e", o.Keplerian.Mu))
+		require.Equal(t, "40.345", fmt.Sprintf("%.3f", gravity.Degrees(o.Keplerian.M)))
+	})
+	t.Run("succeed in converting the state vector to keplerian elements and back", func(t *testing.T) {
+		o := readOPM(t)
+		k := odm.KeplerianElements(o.State.R, o.State.V, o.Keplerian.Mu)
+		r, v := k.StateVectors()
+		require.Less(t, vec3.Magnitude(vec3.Sub(r, o.State.R)), float64(1))
+		require.Less(t, vec3.Magnitude(vec3.Sub(v, o.State.V)), 1e-3)
+	})
+	t.Run("fail on a missing version", func(t *testing.T) {
+		_, err := odm.ReadOPM(strings.NewReader("CREATION_DATE = 2021-11-06T09:23:57\n"))
+		require.ErrorIs(t, err, odm.ErrVersion)
+	})
+	t.Run("fail on a missing keyword", func(t *testing.T) {
+		_, err := odm.ReadOPM(strings.NewReader("CCSDS_OPM_VERS = 2.0\nCREATION_DATE = 2021-11-06T09:23:57\n"))
+		require.ErrorIs(t, err, odm.ErrKeyword)
+	})
+	t.Run("fail on a malformed value", func(t *testing.T) {
+		f, err := os.ReadFile("testdata/opm.kvn")
+		require.NoError(t, err)
+		s := strings.Replace(string(f), "6655.9942", "6655,9942", 1)
+		_, err = odm.ReadOPM(strings.NewReader(s))
+		require.ErrorIs(t, err, odm.ErrValue)
+	})
+}
+
+func TestWriteOPM(t *testing.T) {
+	o := readOPM(t)
+	k := odm.KeplerianElements(o.State.R, o.State.V, o.Keplerian.Mu)
+	o.Keplerian = &k
+
+	t.Run("succeed in a KVN round trip", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		require.NoError(t, odm.WriteOPM(&buf, o))
+		require.Contains(t, buf.String(), "MEAN_ANOMALY")
+		got, err := odm.ReadOPM(&buf)
+		require.NoError(t, err)
+		require.Equal(t, o.Header, got.Header)
+		require.Equal(t, o.Metadata, got.Metadata)
+		require.Equal(t, o.State.Epoch, got.State.Epoch)
+		require.Equal(t, fmt.Sprintf("%.6f", o.State.R), fmt.Sprintf("%.6f", got.State.R))
+		require.Equal(t, fmt.Sprintf("%.9f", o.Keplerian.M), fmt.Sprintf("%.9f", got.Keplerian.M))
+	})
+	t.Run("succeed in an XML round trip", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		require.NoError(t, odm.WriteOPMXML(&buf, o))
+		require.Contains(t, buf.String(), `<opm id="CCSDS_OPM_VERS" version="2.0">`)
+		require.Contains(t, buf.String(), `<X units="km">6655.9942</X>`)
+		got, err := odm.ReadOPMXML(&buf)
+		require.NoError(t, err)
+		require.Equal(t, o.Header, got.Header)
+		require.Equal(t, o.Metadata, got.Metadata)
+		require.Equal(t, o.State.Epoch, got.State.Epoch)
+		require.Equal(t, fmt.Sprintf("%.6f", o.State.V), fmt.Sprintf("%.6f", got.State.V))
+		require.Equal(t, fmt.Sprintf("%.9f", o.Keplerian.W), fmt.Sprintf("%.9f", got.Keplerian.W))
+	})
+	t.Run("succeed in writing an OPM without keplerian elements", func(t *testing.T) {
+		o := o
+		o.Keplerian = nil
+		buf := bytes.Buffer{}
+		require.NoError(t, odm.WriteOPMXML(&buf, o))
+		require.NotContains(t, buf.String(), "keplerianElements")
+		got, err := odm.ReadOPMXML(&buf)
+		require.NoError(t, err)
+		require.Nil(t, got.Keplerian)
+	})
+	t.Run("fail on a missing GM in either encoding", func(t *testing.T) {
+		f, err := os.ReadFile("testdata/opm.kvn")
+		require.NoError(t, err)
+		kvn := strings.Replace(string(f), "GM = 398600.4415 [km**3/s**2]\n", "", 1)
+		_, err = odm.ReadOPM(strings.NewReader(kvn))
+		require.ErrorIs(t, err, odm.ErrKeyword)
+
+		buf := bytes.Buffer{}
+		require.NoError(t, odm.WriteOPMXML(&buf, o))
+		xml := regexp.MustCompile(`\s*<GM[^>]*>[^<]*</GM>`).ReplaceAllString(buf.String(), "")
+		_, err = odm.ReadOPMXML(strings.NewReader(xml))
+		require.ErrorIs(t, err, odm.ErrKeyword)
+		require.Contains(t, err.Error(), "GM")
+	})
+	t.Run("fail on a missing state vector component", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		require.NoError(t, odm.WriteOPMXML(&buf, o))
+		xml := regexp.MustCompile(`\s*<Y_DOT[^>]*>[^<]*</Y_DOT>`).ReplaceAllString(buf.String(), "")
+		_, err := odm.ReadOPMXML(strings.NewReader(xml))
+		require.ErrorIs(t, err, odm.ErrKeyword)
+	})
+}
+
+func TestReadOEM(t *testing.T) {
+	t.Run("succeed in reading a KVN orbit ephemeris message", func(t *testing.T) {
+		o := readOEM(t)
+		require.Equal(t, "NASA/JPL", o.Header.Originator)
+		require.Len(t, o.Segments, 2)
+		seg := o.Segments[0]
+		require.Equal(t, "MARS BARYCENTER", seg.CenterName)
+		require.Equal(t, "HERMITE", seg.Interpolation)
+		require.Equal(t, 7, seg.InterpolationDegree)
+		require.Equal(t, time.Date(1996, 12, 18, 12, 10, 0, 331000000, time.UTC), seg.UseableStartTime)
+		require.Len(t, seg.States, 3)
+		require.Equal(t, "[2776033 -336859 -2008682]", fmt.Sprintf("%.0f", seg.States[2].R))
+	})
+	t.Run("succeed in reading ordinal dates", func(t *testing.T) {
+		o := readOEM(t)
+		seg := o.Segments[1]
+		require.Equal(t, time.Date(1996, 12, 28, 0, 0, 0, 0, time.UTC), seg.StartTime)
+		require.True(t, seg.UseableStartTime.IsZero())
+		require.Len(t, seg.States, 2)
+	})
+	t.Run("fail on a malformed data line", func(t *testing.T) {
+		f, err := os.ReadFile("testdata/oem.kvn")
+		require.NoError(t, err)
+		s := strings.Replace(string(f), " -1.04195", "", 1)
+		_, err = odm.ReadOEM(strings.NewReader(s))
+		require.ErrorIs(t, err, odm.ErrValue)
+	})
+	t.Run("fail on a missing META_STOP", func(t *testing.T) {
+		_, err := odm.ReadOEM(strings.NewReader("CCSDS_OEM_VERS = 2.0\nMETA_START\nOBJECT_NAME = X\n"))
+		require.ErrorIs(t, err, odm.ErrStructure)
+	})
+}
+
+func TestWriteOEM(t *testing.T) {
+	o := readOEM(t)
+
+	t.Run("succeed in a KVN round trip", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		require.NoError(t, odm.WriteOEM(&buf, o))
+		got, err := odm.ReadOEM(&buf)
+		require.NoError(t, err)
+		require.Equal(t, o, got)
+	})
+	t.Run("succeed in an XML round trip", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		require.NoError(t, odm.WriteOEMXML(&buf, o))
+		got, err := odm.ReadOEMXML(&buf)
+		require.NoError(t, err)
+		require.Equal(t, o, got)
+	})
+}
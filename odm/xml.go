@@ -0,0 +1,327 @@
+package odm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+type xmlHeader struct {
+	Comments     []string `xml:"COMMENT"`
+	CreationDate string   `xml:"CREATION_DATE"`
+	Originator   string   `xml:"ORIGINATOR"`
+}
+
+type xmlMetadata struct {
+	Comments            []string `xml:"COMMENT"`
+	ObjectName          string   `xml:"OBJECT_NAME"`
+	ObjectID            string   `xml:"OBJECT_ID"`
+	CenterName          string   `xml:"CENTER_NAME"`
+	RefFrame            string   `xml:"REF_FRAME"`
+	TimeSystem          string   `xml:"TIME_SYSTEM"`
+	StartTime           string   `xml:"START_TIME,omitempty"`
+	UseableStartTime    string   `xml:"USEABLE_START_TIME,omitempty"`
+	UseableStopTime     string   `xml:"USEABLE_STOP_TIME,omitempty"`
+	StopTime            string   `xml:"STOP_TIME,omitempty"`
+	Interpolation       string   `xml:"INTERPOLATION,omitempty"`
+	InterpolationDegree int      `xml:"INTERPOLATION_DEGREE,omitempty"`
+}
+
+type xmlValue struct {
+	Units string  `xml:"units,attr,omitempty"`
+	Value float64 `xml:",chardata"`
+}
+
+type xmlState struct {
+	Comments []string  `xml:"COMMENT"`
+	Epoch    string    `xml:"EPOCH"`
+	X        *xmlValue `xml:"X"`
+	Y        *xmlValue `xml:"Y"`
+	Z        *xmlValue `xml:"Z"`
+	XDot     *xmlValue `xml:"X_DOT"`
+	YDot     *xmlValue `xml:"Y_DOT"`
+	ZDot     *xmlValue `xml:"Z_DOT"`
+}
+
+type xmlKeplerian struct {
+	Comments        []string  `xml:"COMMENT"`
+	SemiMajorAxis   *xmlValue `xml:"SEMI_MAJOR_AXIS"`
+	Eccentricity    *xmlValue `xml:"ECCENTRICITY"`
+	Inclination     *xmlValue `xml:"INCLINATION"`
+	RAOfAscNode     *xmlValue `xml:"RA_OF_ASC_NODE"`
+	ArgOfPericenter *xmlValue `xml:"ARG_OF_PERICENTER"`
+	TrueAnomaly     *xmlValue `xml:"TRUE_ANOMALY"`
+	MeanAnomaly     *xmlValue `xml:"MEAN_ANOMALY"`
+	GM              *xmlValue `xml:"GM"`
+}
+
+type xmlOPM struct {
+	XMLName  xml.Name      `xml:"opm"`
+	ID       string        `xml:"id,attr"`
+	Version  string        `xml:"version,attr"`
+	Header   xmlHeader     `xml:"header"`
+	Metadata xmlMetadata   `xml:"body>segment>metadata"`
+	State    xmlState      `xml:"body>segment>data>stateVector"`
+	Elements *xmlKeplerian `xml:"body>segment>data>keplerianElements"`
+}
+
+type xmlOEMSegment struct {
+	Metadata xmlMetadata `xml:"metadata"`
+	States   []xmlState  `xml:"data>stateVector"`
+}
+
+type xmlOEM struct {
+	XMLName  xml.Name        `xml:"oem"`
+	ID       string          `xml:"id,attr"`
+	Version  string          `xml:"version,attr"`
+	Header   xmlHeader       `xml:"header"`
+	Segments []xmlOEMSegment `xml:"body>segment"`
+}
+
+// ReadOPMXML in XML form.
+func ReadOPMXML(r io.Reader) (OPM, error) {
+	o := OPM{}
+	x := xmlOPM{}
+	if err := xml.NewDecoder(r).Decode(&x); err != nil {
+		return o, fmt.Errorf("%w: %v", ErrStructure, err)
+	}
+	if x.ID != "CCSDS_OPM_VERS" || x.Version == "" {
+		return o, fmt.Errorf("%w: expected CCSDS_OPM_VERS", ErrVersion)
+	}
+
+	var err error
+	if o.Header, err = x.Header.header(x.Version); err != nil {
+		return o, err
+	}
+	o.Metadata = x.Metadata.metadata()
+	if o.State, err = x.State.state(); err != nil {
+		return o, err
+	}
+	if k := x.Elements; k != nil {
+		q := required{}
+		o.Keplerian = &Keplerian{
+			A:   q.value("SEMI_MAJOR_AXIS", k.SemiMajorAxis) * 1000,
+			E:   q.value("ECCENTRICITY", k.Eccentricity),
+			I:   gravity.Radians(q.value("INCLINATION", k.Inclination)),
+			LAN: gravity.Radians(q.value("RA_OF_ASC_NODE", k.RAOfAscNode)),
+			W:   gravity.Radians(q.value("ARG_OF_PERICENTER", k.ArgOfPericenter)),
+			Mu:  q.value("GM", k.GM) * 1e9,
+		}
+		if q.err != nil {
+			return o, q.err
+		}
+		switch {
+		case k.TrueAnomaly != nil:
+			o.Keplerian.M = meanAnomaly(o.Keplerian.E, gravity.Radians(k.TrueAnomaly.Value))
+		case k.MeanAnomaly != nil:
+			o.Keplerian.M = gravity.Radians(k.MeanAnomaly.Value)
+		default:
+			return o, fmt.Errorf("%w: TRUE_ANOMALY or MEAN_ANOMALY", ErrKeyword)
+		}
+	}
+	return o, nil
+}
+
+// WriteOPMXML in XML form.
+func WriteOPMXML(w io.Writer, o OPM) error {
+	x := xmlOPM{
+		ID:       "CCSDS_OPM_VERS",
+		Version:  version(o.Header.Version),
+		Header:   newXMLHeader(o.Header),
+		Metadata: newXMLMetadata(o.Metadata),
+		State:    newXMLState(o.State),
+	}
+	if k := o.Keplerian; k != nil {
+		x.Elements = &xmlKeplerian{
+			SemiMajorAxis:   &xmlValue{"km", k.A / 1000},
+			Eccentricity:    &xmlValue{"", k.E},
+			Inclination:     &xmlValue{"deg", gravity.Degrees(k.I)},
+			RAOfAscNode:     &xmlValue{"deg", gravity.Degrees(k.LAN)},
+			ArgOfPericenter: &xmlValue{"deg", gravity.Degrees(k.W)},
+			MeanAnomaly:     &xmlValue{"deg", gravity.Degrees(k.M)},
+			GM:              &xmlValue{"km**3/s**2", k.Mu / 1e9},
+		}
+	}
+	return encode(w, x)
+}
+
+// ReadOEMXML in XML form.
+func ReadOEMXML(r io.Reader) (OEM, error) {
+	o := OEM{}
+	x := xmlOEM{}
+	if err := xml.NewDecoder(r).Decode(&x); err != nil {
+		return o, fmt.Errorf("%w: %v", ErrStructure, err)
+	}
+	if x.ID != "CCSDS_OEM_VERS" || x.Version == "" {
+		return o, fmt.Errorf("%w: expected CCSDS_OEM_VERS", ErrVersion)
+	}
+
+	var err error
+	if o.Header, err = x.Header.header(x.Version); err != nil {
+		return o, err
+	}
+	for _, xs := range x.Segments {
+		seg := Segment{
+			Metadata:            xs.Metadata.metadata(),
+			Interpolation:       xs.Metadata.Interpolation,
+			InterpolationDegree: xs.Metadata.InterpolationDegree,
+		}
+		for _, t := range []struct {
+			dst      *time.Time
+			src      string
+			optional bool
+		}{
+			{&seg.StartTime, xs.Metadata.StartTime, false},
+			{&seg.StopTime, xs.Metadata.StopTime, false},
+			{&seg.UseableStartTime, xs.Metadata.UseableStartTime, true},
+			{&seg.UseableStopTime, xs.Metadata.UseableStopTime, true},
+		} {
+			if t.src == "" && t.optional {
+				continue
+			}
+			if *t.dst, err = parseTime(t.src); err != nil {
+				return o, err
+			}
+		}
+		for _, s := range xs.States {
+			state, err := s.state()
+			if err != nil {
+				return o, err
+			}
+			seg.States = append(seg.States, state)
+		}
+		o.Segments = append(o.Segments, seg)
+	}
+	return o, nil
+}
+
+// WriteOEMXML in XML form.
+func WriteOEMXML(w io.Writer, o OEM) error {
+	x := xmlOEM{
+		ID:      "CCSDS_OEM_VERS",
+		Version: version(o.Header.Version),
+		Header:  newXMLHeader(o.Header),
+	}
+	for _, seg := range o.Segments {
+		xs := xmlOEMSegment{Metadata: newXMLMetadata(seg.Metadata)}
+		xs.Metadata.StartTime = formatTime(seg.StartTime)
+		xs.Metadata.StopTime = formatTime(seg.StopTime)
+		if !seg.UseableStartTime.IsZero() {
+			xs.Metadata.UseableStartTime = formatTime(seg.UseableStartTime)
+		}
+		if !seg.UseableStopTime.IsZero() {
+			xs.Metadata.UseableStopTime = formatTime(seg.UseableStopTime)
+		}
+		xs.Metadata.Interpolation = seg.Interpolation
+		xs.Metadata.InterpolationDegree = seg.InterpolationDegree
+		for _, s := range seg.States {
+			xs.States = append(xs.States, newXMLState(s))
+		}
+		x.Segments = append(x.Segments, xs)
+	}
+	return encode(w, x)
+}
+
+func encode(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newXMLHeader(h Header) xmlHeader {
+	return xmlHeader{
+		Comments:     h.Comments,
+		CreationDate: formatTime(h.CreationDate),
+		Originator:   h.Originator,
+	}
+}
+
+func (x xmlHeader) header(vers string) (Header, error) {
+	h := Header{Version: vers, Originator: x.Originator, Comments: x.Comments}
+	var err error
+	h.CreationDate, err = parseTime(x.CreationDate)
+	return h, err
+}
+
+func newXMLMetadata(m Metadata) xmlMetadata {
+	return xmlMetadata{
+		Comments:   m.Comments,
+		ObjectName: m.ObjectName,
+		ObjectID:   m.ObjectID,
+		CenterName: m.CenterName,
+		RefFrame:   m.RefFrame,
+		TimeSystem: m.TimeSystem,
+	}
+}
+
+func (x xmlMetadata) metadata() Metadata {
+	return Metadata{
+		ObjectName: x.ObjectName,
+		ObjectID:   x.ObjectID,
+		CenterName: x.CenterName,
+		RefFrame:   x.RefFrame,
+		TimeSystem: x.TimeSystem,
+		Comments:   x.Comments,
+	}
+}
+
+func newXMLState(s State) xmlState {
+	return xmlState{
+		Epoch: formatTime(s.Epoch),
+		X:     &xmlValue{"km", s.R[0] / 1000},
+		Y:     &xmlValue{"km", s.R[1] / 1000},
+		Z:     &xmlValue{"km", s.R[2] / 1000},
+		XDot:  &xmlValue{"km/s", s.V[0] / 1000},
+		YDot:  &xmlValue{"km/s", s.V[1] / 1000},
+		ZDot:  &xmlValue{"km/s", s.V[2] / 1000},
+	}
+}
+
+func (x xmlState) state() (State, error) {
+	epoch, err := parseTime(x.Epoch)
+	if err != nil {
+		return State{}, err
+	}
+	q := required{}
+	s := State{
+		Epoch: epoch,
+		R: f64.Vec3{
+			q.value("X", x.X) * 1000,
+			q.value("Y", x.Y) * 1000,
+			q.value("Z", x.Z) * 1000,
+		},
+		V: f64.Vec3{
+			q.value("X_DOT", x.XDot) * 1000,
+			q.value("Y_DOT", x.YDot) * 1000,
+			q.value("Z_DOT", x.ZDot) * 1000,
+		},
+	}
+	return s, q.err
+}
+
+// required values of an XML message which records the first one missing
+// with the same error as block.string does for KVN messages.
+type required struct {
+	err error
+}
+
+func (q *required) value(key string, v *xmlValue) float64 {
+	if v == nil {
+		if q.err == nil {
+			q.err = fmt.Errorf("%w: %s", ErrKeyword, key)
+		}
+		return 0
+	}
+	return v.Value
+}
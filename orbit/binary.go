@@ -0,0 +1,100 @@
+package orbit
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"golang.org/x/image/math/f64"
+)
+
+var (
+	ErrVersion = errors.New("orbit: unsupported binary encoding version")
+	ErrLength  = errors.New("orbit: unexpected binary encoding length")
+	ErrType    = errors.New("orbit: binary encoding is of another type")
+)
+
+// BinaryVersion of the binary encodings written. The first byte of every
+// encoding is its version and the second is the type encoded, 'E' for
+// Elements and 'S' for State. They are followed by little endian IEEE 754
+// float64 values in the order of the struct fields.
+const BinaryVersion byte = 1
+
+const (
+	ElementsSize = 2 + 8*8 // bytes in the binary encoding of Elements
+	StateSize    = 2 + 8*8 // bytes in the binary encoding of State
+)
+
+const (
+	typeElements byte = 'E'
+	typeState    byte = 'S'
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e Elements) MarshalBinary() ([]byte, error) {
+	return e.AppendBinary(make([]byte, 0, ElementsSize)), nil
+}
+
+// AppendBinary encoding of the elements to b.
+func (e Elements) AppendBinary(b []byte) []byte {
+	return appendFloats(append(b, BinaryVersion, typeElements), e.A, e.E, e.W, e.LAN, e.I, e.M, e.Mu, e.Epoch)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (e *Elements) UnmarshalBinary(b []byte) error {
+	f, err := readFloats(b, typeElements, ElementsSize)
+	if err != nil {
+		return err
+	}
+	*e = Elements{A: f[0], E: f[1], W: f[2], LAN: f[3], I: f[4], M: f[5], Mu: f[6], Epoch: f[7]}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s State) MarshalBinary() ([]byte, error) {
+	return s.AppendBinary(make([]byte, 0, StateSize)), nil
+}
+
+// AppendBinary encoding of the state to b.
+func (s State) AppendBinary(b []byte) []byte {
+	return appendFloats(append(b, BinaryVersion, typeState), s.R[0], s.R[1], s.R[2], s.V[0], s.V[1], s.V[2], s.Mu, s.Epoch)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *State) UnmarshalBinary(b []byte) error {
+	f, err := readFloats(b, typeState, StateSize)
+	if err != nil {
+		return err
+	}
+	s.R = f64.Vec3{f[0], f[1], f[2]}
+	s.V = f64.Vec3{f[3], f[4], f[5]}
+	s.Mu, s.Epoch = f[6], f[7]
+	return nil
+}
+
+func appendFloats(b []byte, values ...float64) []byte {
+	buf := [8]byte{}
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+		b = append(b, buf[:]...)
+	}
+	return b
+}
+
+func readFloats(b []byte, typ byte, size int) ([8]float64, error) {
+	f := [8]float64{}
+	if len(b) != size {
+		return f, fmt.Errorf("%w: %d bytes, expected %d", ErrLength, len(b), size)
+	}
+	if b[0] != BinaryVersion {
+		return f, fmt.Errorf("%w: %d", ErrVersion, b[0])
+	}
+	if b[1] != typ {
+		return f, fmt.Errorf("%w: %q, expected %q", ErrType, b[1], typ)
+	}
+	for k := range f {
+		f[k] = math.Float64frombits(binary.LittleEndian.Uint64(b[2+8*k:]))
+	}
+	return f, nil
+}
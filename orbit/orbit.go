@@ -0,0 +1,88 @@
+// Package orbit provides value types for Keplerian orbital elements and
+// Cartesian state vectors with stable JSON, text and binary encodings so
+// that they can be persisted and sent over the network.
+package orbit
+
+import (
+	"encoding/json"
+
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+// Elements Keplerian Orbital Elements using the conventions of
+// gravity.OrbitalElementsMu.
+//
+// A:     semi-major axis                  (m),
+// E:     eccentricity                     (0-1),
+// W:     argument of periapsis            (rad),
+// LAN:   longitude of ascending node      (rad),
+// I:     inclination                      (rad),
+// M:     mean anomaly at epoch            (rad),
+// Mu:    standard gravitational parameter (m^3/s^2),
+// Epoch: epoch of the elements            (Julian Date).
+type Elements struct {
+	A     float64 `json:"semi_major_axis_m"`
+	E     float64 `json:"eccentricity"`
+	W     float64 `json:"argument_of_periapsis_rad"`
+	LAN   float64 `json:"longitude_of_ascending_node_rad"`
+	I     float64 `json:"inclination_rad"`
+	M     float64 `json:"mean_anomaly_rad"`
+	Mu    float64 `json:"mu_m3_s2"`
+	Epoch float64 `json:"epoch_jd"`
+}
+
+// State Cartesian State Vectors relative to the primary body.
+//
+// R:     position                         (m),
+// V:     velocity                         (m/s),
+// Mu:    standard gravitational parameter (m^3/s^2),
+// Epoch: time of the state                (Julian Date).
+type State struct {
+	R     f64.Vec3 `json:"position_m"`
+	V     f64.Vec3 `json:"velocity_m_s"`
+	Mu    float64  `json:"mu_m3_s2"`
+	Epoch float64  `json:"epoch_jd"`
+}
+
+// elementsJSON and stateJSON have the fields but not the methods of
+// Elements and State so that encoding/json uses their struct tags rather
+// than MarshalText and UnmarshalText.
+type (
+	elementsJSON Elements
+	stateJSON    State
+)
+
+// MarshalJSON implements json.Marshaler.
+func (e Elements) MarshalJSON() ([]byte, error) {
+	return json.Marshal(elementsJSON(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Elements) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, (*elementsJSON)(e))
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s State) MarshalJSON() ([]byte, error) {
+	return json.Marshal(stateJSON(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *State) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, (*stateJSON)(s))
+}
+
+// Elements of the state. See gravity.OrbitalElementsMu for more details.
+func (s State) Elements() Elements {
+	e := Elements{Mu: s.Mu, Epoch: s.Epoch}
+	e.A, e.E, e.W, e.LAN, e.I, e.M = gravity.OrbitalElementsMu(s.R, s.V, s.Mu)
+	return e
+}
+
+// State at t seconds after the epoch of the elements. See
+// gravity.StateVectorsMu for more details.
+func (e Elements) State(t float64) State {
+	r, v := gravity.StateVectorsMu(e.A, e.E, e.W, e.LAN, e.I, e.M, t, e.Mu)
+	return State{R: r, V: v, Mu: e.Mu, Epoch: e.Epoch + t/86400}
+}
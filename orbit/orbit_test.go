@@ -0,0 +1,165 @@
+package orbit_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/epoch"
+	"github.com/wafer-bw/gorbit/orbit"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+var elements = orbit.Elements{
+	A: 7000e3, E: 0.01, W: 0.5, LAN: 1.5, I: 0.9, M: 2.5,
+	Mu: bodies.Earth.GM, Epoch: epoch.J2000,
+}
+
+func TestConversion(t *testing.T) {
+	t.Run("succeed in converting elements to a state and back", func(t *testing.T) {
+		s := elements.State(0)
+		require.Equal(t, elements.Mu, s.Mu)
+		require.Equal(t, elements.Epoch, s.Epoch)
+		e := s.Elements()
+		require.Equal(t, fmt.Sprintf("%.3f", elements.A), fmt.Sprintf("%.3f", e.A))
+		require.Equal(t, fmt.Sprintf("%.9f", elements.M), fmt.Sprintf("%.9f", e.M))
+	})
+	t.Run("succeed in advancing the epoch", func(t *testing.T) {
+		s := elements.State(43200)
+		require.Equal(t, epoch.J2000+0.5, s.Epoch)
+	})
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("succeed in encoding elements with units in field names", func(t *testing.T) {
+		b, err := json.Marshal(orbit.Elements{A: 1, E: 2, W: 3, LAN: 4, I: 5, M: 6, Mu: 7, Epoch: 8})
+		require.NoError(t, err)
+		require.Equal(t, `{"semi_major_axis_m":1,"eccentricity":2,"argument_of_periapsis_rad":3,`+
+			`"longitude_of_ascending_node_rad":4,"inclination_rad":5,"mean_anomaly_rad":6,"mu_m3_s2":7,"epoch_jd":8}`, string(b))
+	})
+	t.Run("succeed in encoding states with units in field names", func(t *testing.T) {
+		b, err := json.Marshal(orbit.State{R: f64.Vec3{1, 2, 3}, V: f64.Vec3{4, 5, 6}, Mu: 7, Epoch: 8})
+		require.NoError(t, err)
+		require.Equal(t, `{"position_m":[1,2,3],"velocity_m_s":[4,5,6],"mu_m3_s2":7,"epoch_jd":8}`, string(b))
+	})
+	t.Run("succeed in a round trip", func(t *testing.T) {
+		b, err := json.Marshal(elements)
+		require.NoError(t, err)
+		got := orbit.Elements{}
+		require.NoError(t, json.Unmarshal(b, &got))
+		require.Equal(t, elements, got)
+	})
+	t.Run("succeed in a state round trip", func(t *testing.T) {
+		s := elements.State(600)
+		b, err := json.Marshal(s)
+		require.NoError(t, err)
+		got := orbit.State{}
+		require.NoError(t, json.Unmarshal(b, &got))
+		require.Equal(t, s, got)
+	})
+}
+
+func TestText(t *testing.T) {
+	t.Run("succeed in encoding elements as name value pairs", func(t *testing.T) {
+		b, err := orbit.Elements{A: 7e6, E: 0.5, W: 3, LAN: 4, I: 5, M: 6, Mu: 7, Epoch: 8}.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, "semi_major_axis_m=7e+06 eccentricity=0.5 argument_of_periapsis_rad=3 "+
+			"longitude_of_ascending_node_rad=4 inclination_rad=5 mean_anomaly_rad=6 mu_m3_s2=7 epoch_jd=8", string(b))
+	})
+	t.Run("succeed in encoding states with comma separated vectors", func(t *testing.T) {
+		b, err := orbit.State{R: f64.Vec3{1, 2, 3}, V: f64.Vec3{4, 5, 6.5}, Mu: 7, Epoch: 8}.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, "position_m=1,2,3 velocity_m_s=4,5,6.5 mu_m3_s2=7 epoch_jd=8", string(b))
+	})
+	t.Run("succeed in an elements round trip", func(t *testing.T) {
+		b, err := elements.MarshalText()
+		require.NoError(t, err)
+		got := orbit.Elements{}
+		require.NoError(t, got.UnmarshalText(b))
+		require.Equal(t, elements, got)
+	})
+	t.Run("succeed in a state round trip", func(t *testing.T) {
+		s := elements.State(600)
+		b, err := s.MarshalText()
+		require.NoError(t, err)
+		got := orbit.State{}
+		require.NoError(t, got.UnmarshalText(b))
+		require.Equal(t, s, got)
+	})
+	t.Run("fail on a missing field", func(t *testing.T) {
+		err := (&orbit.State{}).UnmarshalText([]byte("position_m=1,2,3 velocity_m_s=4,5,6 mu_m3_s2=7"))
+		require.ErrorIs(t, err, orbit.ErrText)
+	})
+	t.Run("fail on a missing vector component", func(t *testing.T) {
+		err := (&orbit.State{}).UnmarshalText([]byte("position_m=1,2 velocity_m_s=4,5,6 mu_m3_s2=7 epoch_jd=8"))
+		require.ErrorIs(t, err, orbit.ErrText)
+	})
+	t.Run("fail on a malformed value", func(t *testing.T) {
+		b, err := elements.MarshalText()
+		require.NoError(t, err)
+		b = []byte(strings.Replace(string(b), "eccentricity=0.01", "eccentricity=0,01", 1))
+		got := orbit.Elements{}
+		require.ErrorIs(t, got.UnmarshalText(b), orbit.ErrText)
+		require.Equal(t, orbit.Elements{}, got)
+	})
+	t.Run("fail to decode a state as elements", func(t *testing.T) {
+		b, err := elements.State(0).MarshalText()
+		require.NoError(t, err)
+		require.ErrorIs(t, (&orbit.Elements{}).UnmarshalText(b), orbit.ErrText)
+	})
+}
+
+func TestBinary(t *testing.T) {
+	t.Run("succeed in an elements round trip", func(t *testing.T) {
+		b, err := elements.MarshalBinary()
+		require.NoError(t, err)
+		require.Len(t, b, orbit.ElementsSize)
+		got := orbit.Elements{}
+		require.NoError(t, got.UnmarshalBinary(b))
+		require.Equal(t, elements, got)
+	})
+	t.Run("succeed in a state round trip", func(t *testing.T) {
+		s := elements.State(600)
+		b, err := s.MarshalBinary()
+		require.NoError(t, err)
+		require.Len(t, b, orbit.StateSize)
+		got := orbit.State{}
+		require.NoError(t, got.UnmarshalBinary(b))
+		require.Equal(t, s, got)
+		require.Equal(t, float64(0), vec3.Magnitude(vec3.Sub(s.R, got.R)))
+	})
+	t.Run("succeed in appending several encodings to one buffer", func(t *testing.T) {
+		s := elements.State(0)
+		b := elements.AppendBinary(nil)
+		b = s.AppendBinary(b)
+		require.Len(t, b, orbit.ElementsSize+orbit.StateSize)
+		got := orbit.State{}
+		require.NoError(t, got.UnmarshalBinary(b[orbit.ElementsSize:]))
+		require.Equal(t, s, got)
+	})
+	t.Run("succeed in encoding a known layout", func(t *testing.T) {
+		b, err := orbit.Elements{A: 1}.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, "0145000000000000f03f", fmt.Sprintf("%x", b[:10]))
+	})
+	t.Run("fail on an unexpected length", func(t *testing.T) {
+		err := (&orbit.Elements{}).UnmarshalBinary(make([]byte, 10))
+		require.ErrorIs(t, err, orbit.ErrLength)
+	})
+	t.Run("fail on an unsupported version", func(t *testing.T) {
+		err := (&orbit.State{}).UnmarshalBinary(make([]byte, orbit.StateSize))
+		require.ErrorIs(t, err, orbit.ErrVersion)
+	})
+	t.Run("fail to decode elements as a state and a state as elements", func(t *testing.T) {
+		b, err := elements.MarshalBinary()
+		require.NoError(t, err)
+		require.ErrorIs(t, (&orbit.State{}).UnmarshalBinary(b), orbit.ErrType)
+		b, err = elements.State(0).MarshalBinary()
+		require.NoError(t, err)
+		require.ErrorIs(t, (&orbit.Elements{}).UnmarshalBinary(b), orbit.ErrType)
+	})
+}
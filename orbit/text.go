@@ -0,0 +1,120 @@
+package orbit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrText = errors.New("orbit: malformed text encoding")
+
+// MarshalText implements encoding.TextMarshaler. The text encoding is a
+// single line of space separated name=value pairs, named and ordered like
+// the JSON fields, with the components of vectors separated by commas.
+// Values are written with the fewest digits that read back exactly.
+//
+//	semi_major_axis_m=7e+06 eccentricity=0.01 argument_of_periapsis_rad=0.5 ...
+func (e Elements) MarshalText() ([]byte, error) {
+	return appendText(nil, e.fields()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Every field must be
+// present in the order written by MarshalText.
+func (e *Elements) UnmarshalText(text []byte) error {
+	x := Elements{}
+	if err := readText(text, x.fields()); err != nil {
+		return err
+	}
+	*e = x
+	return nil
+}
+
+func (e *Elements) fields() []field {
+	return []field{
+		{"semi_major_axis_m", []*float64{&e.A}},
+		{"eccentricity", []*float64{&e.E}},
+		{"argument_of_periapsis_rad", []*float64{&e.W}},
+		{"longitude_of_ascending_node_rad", []*float64{&e.LAN}},
+		{"inclination_rad", []*float64{&e.I}},
+		{"mean_anomaly_rad", []*float64{&e.M}},
+		{"mu_m3_s2", []*float64{&e.Mu}},
+		{"epoch_jd", []*float64{&e.Epoch}},
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler. See Elements.MarshalText
+// for more details.
+//
+//	position_m=7e+06,0,0 velocity_m_s=0,7546,0 mu_m3_s2=3.986004418e+14 ...
+func (s State) MarshalText() ([]byte, error) {
+	return appendText(nil, s.fields()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Every field must be
+// present in the order written by MarshalText.
+func (s *State) UnmarshalText(text []byte) error {
+	x := State{}
+	if err := readText(text, x.fields()); err != nil {
+		return err
+	}
+	*s = x
+	return nil
+}
+
+func (s *State) fields() []field {
+	return []field{
+		{"position_m", []*float64{&s.R[0], &s.R[1], &s.R[2]}},
+		{"velocity_m_s", []*float64{&s.V[0], &s.V[1], &s.V[2]}},
+		{"mu_m3_s2", []*float64{&s.Mu}},
+		{"epoch_jd", []*float64{&s.Epoch}},
+	}
+}
+
+// field of the text encoding with one value per component.
+type field struct {
+	name   string
+	values []*float64
+}
+
+func appendText(b []byte, fields []field) []byte {
+	for k, f := range fields {
+		if k > 0 {
+			b = append(b, ' ')
+		}
+		b = append(b, f.name...)
+		b = append(b, '=')
+		for j, v := range f.values {
+			if j > 0 {
+				b = append(b, ',')
+			}
+			b = strconv.AppendFloat(b, *v, 'g', -1, 64)
+		}
+	}
+	return b
+}
+
+func readText(text []byte, fields []field) error {
+	pairs := strings.Fields(string(text))
+	if len(pairs) != len(fields) {
+		return fmt.Errorf("%w: %d fields, expected %d", ErrText, len(pairs), len(fields))
+	}
+	for k, f := range fields {
+		name, value, ok := strings.Cut(pairs[k], "=")
+		if !ok || name != f.name {
+			return fmt.Errorf("%w: %q, expected %s", ErrText, pairs[k], f.name)
+		}
+		components := strings.Split(value, ",")
+		if len(components) != len(f.values) {
+			return fmt.Errorf("%w: %s has %d components, expected %d", ErrText, name, len(components), len(f.values))
+		}
+		for j, c := range components {
+			v, err := strconv.ParseFloat(c, 64)
+			if err != nil {
+				return fmt.Errorf("%w: %s = %q", ErrText, name, value)
+			}
+			*f.values[j] = v
+		}
+	}
+	return nil
+}
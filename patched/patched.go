@@ -0,0 +1,190 @@
+// Package patched propagates spacecraft through a hierarchy of on-rails
+// bodies using patched conics. Within the sphere of influence of a body
+// only its gravity is considered and the spacecraft follows a conic. When
+// the spacecraft leaves the sphere of influence of its primary, or enters
+// that of one of its children, its state is re-expressed relative to the
+// new primary and a new conic begins. The hierarchy of bodies is that of
+// package rails.
+package patched
+
+import (
+	"errors"
+	"math"
+
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/rails"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+var (
+	ErrBody   = errors.New("patched: body index out of range")
+	ErrParent = rails.ErrParent
+	ErrStep   = errors.New("patched: step and tolerance must be greater than 0")
+)
+
+// Body of a System on-rails around its parent. See rails.Body for more
+// details.
+type Body = rails.Body
+
+// System of bodies where every body except the root orbits its parent.
+// Parents must appear before their children. A rails.System can be
+// converted to a System to propagate spacecraft through it.
+type System rails.System
+
+// Validate that every parent is -1 or the index of an earlier body.
+func (s System) Validate() error {
+	return rails.System(s).Validate()
+}
+
+// State of body k relative to its parent t seconds after epoch. The root
+// is always at rest at the origin.
+func (s System) State(k int, t float64) (f64.Vec3, f64.Vec3) {
+	return rails.System(s).State(k, t)
+}
+
+// SOI radius of body k (m). See rails.System.SOI for more details.
+func (s System) SOI(k int) float64 {
+	return rails.System(s).SOI(k)
+}
+
+// Segment of a trajectory which follows a single conic around Body.
+//
+// Body: index of the primary body in the System,
+// T0:   time since epoch the segment starts (s),
+// T1:   time since epoch the segment ends   (s),
+// R:    position relative to Body at T0     (m),
+// V:    velocity relative to Body at T0     (m/s),
+// Mu:   standard gravitational parameter of Body (m^3/s^2).
+type Segment struct {
+	Body int
+	T0   float64
+	T1   float64
+	R    f64.Vec3
+	V    f64.Vec3
+	Mu   float64
+}
+
+// State relative to the primary of the segment t seconds after epoch.
+func (g Segment) State(t float64) (f64.Vec3, f64.Vec3) {
+	return gravity.Propagate(g.R, g.V, t-g.T0, g.Mu)
+}
+
+// Elements of the conic relative to the primary of the segment with the
+// mean anomaly at T0. Escape, capture and flyby segments are usually
+// hyperbolic. See gravity.OrbitalElementsMu for more details.
+func (g Segment) Elements() (a, e, w, lan, i, m float64) {
+	return gravity.OrbitalElementsMu(g.R, g.V, g.Mu)
+}
+
+// Trajectory of consecutive segments in time order.
+type Trajectory []Segment
+
+// State at t seconds after epoch relative to the primary of the segment
+// containing t. Times outside the trajectory use the nearest segment.
+func (tr Trajectory) State(t float64) (body int, r, v f64.Vec3) {
+	g := tr[len(tr)-1]
+	for _, s := range tr {
+		if t <= s.T1 {
+			g = s
+			break
+		}
+	}
+	r, v = g.State(t)
+	return g.Body, r, v
+}
+
+// Propagate a spacecraft from t0 to t1 seconds after epoch. The System
+// is validated first.
+//
+// body: index of the primary body in the System,
+// r:    position relative to the primary at t0 (m),
+// v:    velocity relative to the primary at t0 (m/s),
+// step: time between sphere of influence checks (s),
+// tol:  time tolerance of transitions (s).
+//
+// Sphere of influence crossings are searched for every step and located
+// to within tol by bisection. A step shorter than the time the
+// spacecraft spends crossing the smallest sphere of influence it may
+// encounter is required to not miss it. Transitions are placed just past
+// each crossing so that the spacecraft is always inside the sphere of
+// influence of the primary of its segment.
+func (s System) Propagate(body int, r, v f64.Vec3, t0, t1, step, tol float64) (Trajectory, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	if body < 0 || body >= len(s) {
+		return nil, ErrBody
+	}
+	if !(step > 0) || !(tol > 0) {
+		return nil, ErrStep
+	}
+	g := Segment{Body: body, T0: t0, T1: t1, R: r, V: v, Mu: s[body].Mu}
+	tr := Trajectory{}
+	for {
+		t, next, ok := s.transition(g, t1, step, tol)
+		if !ok {
+			return append(tr, g), nil
+		}
+		g.T1 = t
+		tr = append(tr, g)
+		g = next
+	}
+}
+
+// transition finds the first sphere of influence crossing of g before t1
+// and returns the segment following it.
+func (s System) transition(g Segment, t1, step, tol float64) (float64, Segment, bool) {
+	children := []int{}
+	for k, b := range s {
+		if b.Parent == g.Body && k != g.Body {
+			children = append(children, k)
+		}
+	}
+	soi := s.SOI(g.Body)
+
+	// crossing of the sphere of influence of the primary (-1) or a child.
+	crossing := func(t float64) (int, bool) {
+		r, _ := g.State(t)
+		if vec3.Magnitude(r) >= soi {
+			return -1, true
+		}
+		for _, c := range children {
+			rc, _ := s.State(c, t)
+			if vec3.Magnitude(vec3.Sub(r, rc)) <= s.SOI(c) {
+				return c, true
+			}
+		}
+		return 0, false
+	}
+
+	for lo := g.T0; lo < t1; lo += step {
+		hi := math.Min(lo+step, t1)
+		if _, ok := crossing(hi); !ok {
+			continue
+		}
+		for hi-lo > tol {
+			mid := (lo + hi) / 2
+			if _, ok := crossing(mid); ok {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		to, _ := crossing(hi)
+		r, v := g.State(hi)
+		next := Segment{T0: hi, T1: t1}
+		if to < 0 {
+			rp, vp := s.State(g.Body, hi)
+			next.Body = s[g.Body].Parent
+			next.R, next.V = vec3.Add(r, rp), vec3.Add(v, vp)
+		} else {
+			rc, vc := s.State(to, hi)
+			next.Body = to
+			next.R, next.V = vec3.Sub(r, rc), vec3.Sub(v, vc)
+		}
+		next.Mu = s[next.Body].Mu
+		return hi, next, true
+	}
+	return 0, Segment{}, false
+}
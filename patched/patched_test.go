@@ -0,0 +1,143 @@
+package patched_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/patched"
+	"github.com/wafer-bw/gorbit/rails"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+const (
+	sun = iota
+	earth
+	moon
+)
+
+var system = patched.System{
+	sun:   {Name: "Sun", Mu: bodies.Sun.GM, Parent: -1},
+	earth: {Name: "Earth", Mu: bodies.Earth.GM, Parent: sun, A: gravity.AU, E: 0.0167},
+	moon:  {Name: "Moon", Mu: bodies.Moon.GM, Parent: earth, A: 384400e3, E: 0.0549, SOI: bodies.Moon.SOI},
+}
+
+func TestSOI(t *testing.T) {
+	t.Run("succeed in using the configured sphere of influence", func(t *testing.T) {
+		require.Equal(t, bodies.Moon.SOI, system.SOI(moon))
+	})
+	t.Run("succeed in deriving the laplace sphere of influence", func(t *testing.T) {
+		require.Equal(t, "9.25e+08", fmt.Sprintf("%.2e", system.SOI(earth)))
+	})
+	t.Run("succeed in giving the root an infinite sphere of influence", func(t *testing.T) {
+		require.True(t, math.IsInf(system.SOI(sun), 1))
+	})
+}
+
+func TestElements(t *testing.T) {
+	t.Run("succeed in calculating elliptical elements", func(t *testing.T) {
+		g := patched.Segment{R: f64.Vec3{7000e3, 0, 0}, V: f64.Vec3{0, 8000, 0}, Mu: bodies.Earth.GM}
+		a, e, _, _, _, m := g.Elements()
+		a2, e2, _, _, _, m2 := gravity.OrbitalElementsMu(g.R, g.V, g.Mu)
+		require.Equal(t, []float64{a2, e2, m2}, []float64{a, e, m})
+	})
+	t.Run("succeed in calculating hyperbolic elements", func(t *testing.T) {
+		// escape from earth starting 10 minutes before periapsis.
+		rp, vp := f64.Vec3{7000e3, 0, 0}, f64.Vec3{0, 12e3, 0}
+		r, v := gravity.Propagate(rp, vp, -600, bodies.Earth.GM)
+		g := patched.Segment{T0: -600, T1: 86400, R: r, V: v, Mu: bodies.Earth.GM}
+
+		a, e, _, _, _, m := g.Elements()
+		require.Less(t, a, 0.0)
+		require.Greater(t, e, 1.0)
+		require.Less(t, m, 0.0)
+		require.Equal(t, fmt.Sprintf("%.3f", vec3.Magnitude(rp)), fmt.Sprintf("%.3f", a*(1-e)))
+
+		n := math.Sqrt(bodies.Earth.GM / -(a * a * a))
+		require.Equal(t, "600.000", fmt.Sprintf("%.3f", -m/n))
+
+		// the mean anomaly advances at the mean motion along the segment.
+		r2, v2 := g.State(3600)
+		_, _, _, _, _, m2 := patched.Segment{R: r2, V: v2, Mu: g.Mu}.Elements()
+		require.Equal(t, fmt.Sprintf("%.6f", m+n*4200), fmt.Sprintf("%.6f", m2))
+		nu := math.Atan2(vec3.Cross(rp, r2)[2], vec3.Dot(rp, r2))
+		require.Equal(t, fmt.Sprintf("%.6f", nu), fmt.Sprintf("%.6f", gravity.TrueAnomaly(e, m2)))
+	})
+}
+
+func TestPropagate(t *testing.T) {
+	t.Run("succeed in escaping earth into a heliocentric orbit", func(t *testing.T) {
+		r := f64.Vec3{7000e3, 0, 0}
+		v := f64.Vec3{0, 12e3, 0}
+		tr, err := system.Propagate(earth, r, v, 0, 30*86400, 3600, 1e-3)
+		require.NoError(t, err)
+		require.Len(t, tr, 2)
+		require.Equal(t, earth, tr[0].Body)
+		require.Equal(t, sun, tr[1].Body)
+		require.Equal(t, tr[0].T1, tr[1].T0)
+
+		rExit, vExit := tr[0].State(tr[0].T1)
+		require.Equal(t, "9.25e+08", fmt.Sprintf("%.2e", vec3.Magnitude(rExit)))
+
+		rEarth, vEarth := system.State(earth, tr[1].T0)
+		require.Less(t, vec3.Magnitude(vec3.Sub(tr[1].R, vec3.Add(rExit, rEarth))), 1e-3)
+		require.Less(t, vec3.Magnitude(vec3.Sub(tr[1].V, vec3.Add(vExit, vEarth))), 1e-9)
+
+		body, rEnd, _ := tr.State(30 * 86400)
+		require.Equal(t, sun, body)
+		require.Equal(t, "1.0", fmt.Sprintf("%.1f", vec3.Magnitude(rEnd)/gravity.AU))
+	})
+	t.Run("succeed in entering the sphere of influence of the moon", func(t *testing.T) {
+		// Hohmann transfer from low earth orbit reaching apogee where the
+		// moon will be.
+		r1, r2 := 6678e3, 384400e3
+		dv1, _, tof := gravity.Hohmann(r1, r2, bodies.Earth.GM)
+		earthMoon := patched.System{
+			{Name: "Earth", Mu: bodies.Earth.GM, Parent: -1},
+			{Name: "Moon", Mu: bodies.Moon.GM, Parent: 0, A: r2, SOI: bodies.Moon.SOI},
+		}
+		earthMoon[1].M0 = gravity.Pi - tof*math.Sqrt(bodies.Earth.GM/(r2*r2*r2))
+
+		r := f64.Vec3{r1, 0, 0}
+		v := f64.Vec3{0, math.Sqrt(bodies.Earth.GM/r1) + dv1, 0}
+		tr, err := earthMoon.Propagate(0, r, v, 0, tof, 600, 1e-3)
+		require.NoError(t, err)
+		require.Len(t, tr, 2)
+		require.Equal(t, 1, tr[1].Body)
+		require.Less(t, tr[1].T0, tof)
+		require.Equal(t, fmt.Sprintf("%.0f", bodies.Moon.SOI/1000), fmt.Sprintf("%.0f", vec3.Magnitude(tr[1].R)/1000))
+	})
+	t.Run("succeed in staying on one conic without crossings", func(t *testing.T) {
+		tr, err := system.Propagate(earth, f64.Vec3{7000e3, 0, 0}, f64.Vec3{0, 7546, 0}, 0, 86400, 600, 1e-3)
+		require.NoError(t, err)
+		require.Len(t, tr, 1)
+	})
+	t.Run("succeed in propagating through a rails system", func(t *testing.T) {
+		s := rails.System(system)
+		r, v := f64.Vec3{7000e3, 0, 0}, f64.Vec3{0, 12e3, 0}
+		tr, err := patched.System(s).Propagate(earth, r, v, 0, 30*86400, 3600, 1e-3)
+		require.NoError(t, err)
+		rs, vs := s.Absolute(sun, tr[1].T0)
+		re, ve := s.Absolute(earth, tr[1].T0)
+		r0, v0 := tr[0].State(tr[1].T0)
+		require.Equal(t, fmt.Sprintf("%.0f", vec3.Add(re, r0)), fmt.Sprintf("%.0f", vec3.Add(rs, tr[1].R)))
+		require.Equal(t, fmt.Sprintf("%.3f", vec3.Add(ve, v0)), fmt.Sprintf("%.3f", vec3.Add(vs, tr[1].V)))
+	})
+	t.Run("fail for an invalid system", func(t *testing.T) {
+		s := patched.System{{Name: "a", Parent: 1}, {Name: "b", Parent: -1}}
+		_, err := s.Propagate(1, f64.Vec3{}, f64.Vec3{}, 0, 1, 1, 1)
+		require.ErrorIs(t, err, patched.ErrParent)
+	})
+	t.Run("fail for an unknown body", func(t *testing.T) {
+		_, err := system.Propagate(3, f64.Vec3{}, f64.Vec3{}, 0, 1, 1, 1)
+		require.ErrorIs(t, err, patched.ErrBody)
+	})
+	t.Run("fail for a non positive step", func(t *testing.T) {
+		_, err := system.Propagate(earth, f64.Vec3{}, f64.Vec3{}, 0, 1, 0, 1)
+		require.ErrorIs(t, err, patched.ErrStep)
+	})
+}
@@ -0,0 +1,237 @@
+// Package plot renders orbits, bodies and trajectories to raster images
+// and SVG.
+package plot
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/wafer-bw/gorbit/conic"
+	"github.com/wafer-bw/gorbit/gravity"
+	"golang.org/x/image/math/f64"
+)
+
+const (
+	margin     = 40  // pixels between the plot area and the image edge
+	lineHeight = 14  // pixels between lines of text
+	minRadius  = 3   // minimum radius of bodies in pixels
+	pathPixels = 0.5 // maximum chord error of orbits in pixels
+)
+
+// Projection from the inertial frame onto the image.
+//
+// M: view matrix with rows for the image x (right), y (up) and depth axes,
+// X: label of the image x axis,
+// Y: label of the image y axis.
+type Projection struct {
+	M f64.Mat3
+	X string
+	Y string
+}
+
+var (
+	// TopDown looks down the z axis onto the x-y plane.
+	TopDown = Projection{M: f64.Mat3{1, 0, 0, 0, 1, 0, 0, 0, 1}, X: "x", Y: "y"}
+	// Side looks along the y axis onto the x-z plane.
+	Side = Projection{M: f64.Mat3{1, 0, 0, 0, 0, 1, 0, -1, 0}, X: "x", Y: "z"}
+)
+
+// Plot of orbits, bodies and trajectories around a primary body at the
+// origin. Items are drawn in the order they are added and the view is
+// scaled to fit all of them.
+type Plot struct {
+	Width      int
+	Height     int
+	Title      string
+	Projection Projection
+	Background color.Color
+	Foreground color.Color
+
+	items []item
+}
+
+type item struct {
+	points []f64.Vec3
+	radius float64 // radius of bodies (m), 0 for lines
+	color  color.Color
+	label  string
+}
+
+// New plot of width by height pixels using the TopDown projection with a
+// black background and white axes and text.
+func New(width, height int) *Plot {
+	return &Plot{
+		Width:      width,
+		Height:     height,
+		Projection: TopDown,
+		Background: color.Black,
+		Foreground: color.White,
+	}
+}
+
+// Orbit described by Keplerian Orbital Elements. Hyperbolic orbits are
+// drawn out to ten times their periapsis distance. See conic.Path for more
+// details.
+//
+// a:   semi-major axis               (m),
+// e:   eccentricity,
+// w:   argument of periapsis         (rad),
+// lan: longitude of ascending node   (rad),
+// i:   inclination                   (rad).
+func (p *Plot) Orbit(a, e, w, lan, i float64, c color.Color, label string) {
+	rmax := 10 * gravity.Periapsis(a, e)
+	scale := math.Max(gravity.Periapsis(a, e), math.Abs(a)) / float64(p.Width)
+	p.items = append(p.items, item{
+		points: conic.Path(a, e, w, lan, i, rmax, pathPixels*scale),
+		color:  c,
+		label:  label,
+	})
+}
+
+// Trajectory through points (m) such as the output of a propagator.
+func (p *Plot) Trajectory(points []f64.Vec3, c color.Color, label string) {
+	p.items = append(p.items, item{points: points, color: c, label: label})
+}
+
+// Body at position (m) drawn as a disc of radius (m) but never smaller
+// than a few pixels.
+func (p *Plot) Body(position f64.Vec3, radius float64, c color.Color, label string) {
+	p.items = append(p.items, item{points: []f64.Vec3{position}, radius: radius, color: c, label: label})
+}
+
+// Image of the plot.
+func (p *Plot) Image() *image.RGBA {
+	r := newRaster(p.Width, p.Height, p.Background)
+	p.draw(r)
+	return r.img
+}
+
+// SVG document of the plot.
+func (p *Plot) SVG() string {
+	s := newSVG(p.Width, p.Height, p.Background)
+	p.draw(s)
+	return s.String()
+}
+
+// canvas the plot is drawn onto in pixel coordinates with y down.
+type canvas interface {
+	polyline(points []f64.Vec2, c color.Color, width float64)
+	disc(center f64.Vec2, radius float64, c color.Color)
+	text(at f64.Vec2, s string, c color.Color)
+}
+
+// view maps projected coordinates onto pixels.
+type view struct {
+	m      f64.Mat3
+	center f64.Vec2 // projected coordinates at the center of the image
+	scale  float64  // pixels per meter
+	width  float64
+	height float64
+}
+
+func (v view) pixel(x f64.Vec3) f64.Vec2 {
+	u := v.m[0]*x[0] + v.m[1]*x[1] + v.m[2]*x[2]
+	w := v.m[3]*x[0] + v.m[4]*x[1] + v.m[5]*x[2]
+	return f64.Vec2{
+		v.width/2 + (u-v.center[0])*v.scale,
+		v.height/2 - (w-v.center[1])*v.scale,
+	}
+}
+
+func (p *Plot) view() view {
+	v := view{m: p.Projection.M, width: float64(p.Width), height: float64(p.Height), scale: 1}
+	lo := f64.Vec2{0, 0}
+	hi := f64.Vec2{0, 0}
+	for _, it := range p.items {
+		for _, x := range it.points {
+			u := v.m[0]*x[0] + v.m[1]*x[1] + v.m[2]*x[2]
+			w := v.m[3]*x[0] + v.m[4]*x[1] + v.m[5]*x[2]
+			lo = f64.Vec2{math.Min(lo[0], u-it.radius), math.Min(lo[1], w-it.radius)}
+			hi = f64.Vec2{math.Max(hi[0], u+it.radius), math.Max(hi[1], w+it.radius)}
+		}
+	}
+	v.center = f64.Vec2{(lo[0] + hi[0]) / 2, (lo[1] + hi[1]) / 2}
+	sx := (v.width - 2*margin) / (hi[0] - lo[0])
+	sy := (v.height - 2*margin) / (hi[1] - lo[1])
+	if s := math.Min(sx, sy); s > 0 && !math.IsInf(s, 0) {
+		v.scale = s
+	} else if s := math.Max(sx, sy); s > 0 && !math.IsInf(s, 0) {
+		v.scale = s
+	}
+	return v
+}
+
+func (p *Plot) draw(c canvas) {
+	v := p.view()
+	axis := blend(p.Foreground, p.Background)
+
+	origin := v.pixel(f64.Vec3{})
+	c.polyline([]f64.Vec2{{margin / 2, origin[1]}, {v.width - margin/2, origin[1]}}, axis, 1)
+	c.polyline([]f64.Vec2{{origin[0], v.height - margin/2}, {origin[0], margin / 2}}, axis, 1)
+	c.text(f64.Vec2{v.width - margin/2 + 2, origin[1] + 4}, p.Projection.X, axis)
+	c.text(f64.Vec2{origin[0] + 4, margin/2 + 4}, p.Projection.Y, axis)
+
+	for _, it := range p.items {
+		if it.radius > 0 || len(it.points) == 1 {
+			c.disc(v.pixel(it.points[0]), math.Max(minRadius, it.radius*v.scale), it.color)
+			continue
+		}
+		px := make([]f64.Vec2, len(it.points))
+		for k, x := range it.points {
+			px[k] = v.pixel(x)
+		}
+		c.polyline(px, it.color, 1.5)
+	}
+
+	if p.Title != "" {
+		c.text(f64.Vec2{8, lineHeight}, p.Title, p.Foreground)
+	}
+
+	y := float64(lineHeight)
+	for _, it := range p.items {
+		if it.label == "" {
+			continue
+		}
+		x := v.width - 8 - float64(7*len(it.label))
+		c.polyline([]f64.Vec2{{x - 18, y - 4}, {x - 4, y - 4}}, it.color, 3)
+		c.text(f64.Vec2{x, y}, it.label, p.Foreground)
+		y += lineHeight
+	}
+
+	length, label := scaleBar(v.width / 5 / v.scale)
+	bar := length * v.scale
+	base := v.height - 12
+	c.polyline([]f64.Vec2{{8, base - 4}, {8, base}, {8 + bar, base}, {8 + bar, base - 4}}, p.Foreground, 1)
+	c.text(f64.Vec2{8, base - 6}, label, p.Foreground)
+}
+
+// scaleBar length (m) which is a round number no longer than limit (m) and
+// its label.
+func scaleBar(limit float64) (float64, string) {
+	unit, name := 1000.0, "km"
+	if limit >= 0.1*gravity.AU {
+		unit, name = gravity.AU, "au"
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(limit/unit)))
+	length := exp
+	for _, f := range []float64{2, 5, 10} {
+		if f*exp*unit <= limit {
+			length = f * exp
+		}
+	}
+	return length * unit, fmt.Sprintf("%g %s", length, name)
+}
+
+// blend two colors equally.
+func blend(c1, c2 color.Color) color.Color {
+	r1, g1, b1, a1 := c1.RGBA()
+	r2, g2, b2, a2 := c2.RGBA()
+	return color.RGBA64{
+		R: uint16((r1 + r2) / 2),
+		G: uint16((g1 + g2) / 2),
+		B: uint16((b1 + b2) / 2),
+		A: uint16((a1 + a2) / 2),
+	}
+}
@@ -0,0 +1,61 @@
+package plot_test
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/plot"
+	"golang.org/x/image/math/f64"
+)
+
+var red = color.RGBA{R: 255, A: 255}
+
+func newPlot() *plot.Plot {
+	p := plot.New(400, 300)
+	p.Title = "LEO"
+	p.Body(f64.Vec3{}, 6378e3, color.RGBA{B: 255, A: 255}, "Earth")
+	p.Orbit(7000e3, 0, 0, 0, 0, red, "circular <7000 km>")
+	return p
+}
+
+func TestImage(t *testing.T) {
+	t.Run("succeed in rendering orbits and bodies", func(t *testing.T) {
+		img := newPlot().Image()
+		require.Equal(t, 400, img.Bounds().Dx())
+		require.Equal(t, 300, img.Bounds().Dy())
+		require.Equal(t, color.RGBA{A: 255}, img.RGBAAt(1, 150))
+		require.Equal(t, color.RGBA{B: 255, A: 255}, img.RGBAAt(200+30, 150+30))
+
+		// the circular orbit fills the 220 pixel tall plot area
+		found := false
+		for x := 300; x < 320; x++ {
+			if c := img.RGBAAt(x, 150-40); c.R > 128 && c.G < 64 && c.B < 64 {
+				found = true
+			}
+		}
+		require.True(t, found)
+	})
+	t.Run("succeed in rendering an empty plot", func(t *testing.T) {
+		img := plot.New(50, 50).Image()
+		require.Equal(t, 50, img.Bounds().Dx())
+	})
+}
+
+func TestSVG(t *testing.T) {
+	t.Run("succeed in rendering orbits, bodies and labels", func(t *testing.T) {
+		p := newPlot()
+		p.Projection = plot.Side
+		p.Trajectory([]f64.Vec3{{7000e3, 0, 0}, {0, 0, 7000e3}}, color.White, "")
+		s := p.SVG()
+		require.True(t, strings.HasPrefix(s, `<svg xmlns="http://www.w3.org/2000/svg" width="400" height="300"`))
+		require.True(t, strings.HasSuffix(s, "</svg>\n"))
+		require.Contains(t, s, `<circle cx="200.00" cy="155.11"`)
+		require.Contains(t, s, `stroke="#ff0000"`)
+		require.Contains(t, s, "circular &lt;7000 km&gt;")
+		require.Contains(t, s, ">LEO</text>")
+		require.Contains(t, s, ">z</text>")
+		require.Contains(t, s, " km</text>")
+	})
+}
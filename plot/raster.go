@@ -0,0 +1,72 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// raster canvas drawing anti-aliased shapes onto an image.RGBA.
+type raster struct {
+	img *image.RGBA
+	ras *vector.Rasterizer
+}
+
+func newRaster(width, height int, background color.Color) *raster {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	return &raster{img: img, ras: vector.NewRasterizer(width, height)}
+}
+
+func (r *raster) polyline(points []f64.Vec2, c color.Color, width float64) {
+	if len(points) < 2 {
+		return
+	}
+	r.ras.Reset(r.img.Bounds().Dx(), r.img.Bounds().Dy())
+	for k := 1; k < len(points); k++ {
+		a, b := points[k-1], points[k]
+		dx, dy := b[0]-a[0], b[1]-a[1]
+		d := math.Hypot(dx, dy)
+		if d == 0 {
+			continue
+		}
+		nx, ny := -dy/d*width/2, dx/d*width/2
+		// extend each segment by half the width so joints have no gaps
+		ex, ey := dx/d*width/2, dy/d*width/2
+		r.ras.MoveTo(float32(a[0]+nx-ex), float32(a[1]+ny-ey))
+		r.ras.LineTo(float32(b[0]+nx+ex), float32(b[1]+ny+ey))
+		r.ras.LineTo(float32(b[0]-nx+ex), float32(b[1]-ny+ey))
+		r.ras.LineTo(float32(a[0]-nx-ex), float32(a[1]-ny-ey))
+		r.ras.ClosePath()
+	}
+	r.ras.Draw(r.img, r.img.Bounds(), image.NewUniform(c), image.Point{})
+}
+
+func (r *raster) disc(center f64.Vec2, radius float64, c color.Color) {
+	const n = 32
+	r.ras.Reset(r.img.Bounds().Dx(), r.img.Bounds().Dy())
+	r.ras.MoveTo(float32(center[0]+radius), float32(center[1]))
+	for k := 1; k < n; k++ {
+		sin, cos := math.Sincos(2 * math.Pi * float64(k) / n)
+		r.ras.LineTo(float32(center[0]+radius*cos), float32(center[1]+radius*sin))
+	}
+	r.ras.ClosePath()
+	r.ras.Draw(r.img, r.img.Bounds(), image.NewUniform(c), image.Point{})
+}
+
+func (r *raster) text(at f64.Vec2, s string, c color.Color) {
+	d := font.Drawer{
+		Dst:  r.img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(int(at[0]), int(at[1])),
+	}
+	d.DrawString(s)
+}
@@ -0,0 +1,56 @@
+package plot
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"golang.org/x/image/math/f64"
+)
+
+// svg canvas writing SVG elements.
+type svg struct {
+	strings.Builder
+}
+
+func newSVG(width, height int, background color.Color) *svg {
+	s := &svg{}
+	fmt.Fprintf(s, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(s, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(background))
+	return s
+}
+
+func (s *svg) String() string {
+	return s.Builder.String() + "</svg>\n"
+}
+
+func (s *svg) polyline(points []f64.Vec2, c color.Color, width float64) {
+	if len(points) < 2 {
+		return
+	}
+	s.WriteString(`<polyline points="`)
+	for k, p := range points {
+		if k > 0 {
+			s.WriteByte(' ')
+		}
+		fmt.Fprintf(s, "%.2f,%.2f", p[0], p[1])
+	}
+	fmt.Fprintf(s, `" fill="none" stroke="%s" stroke-width="%g" stroke-linejoin="round"/>`+"\n", hex(c), width)
+}
+
+func (s *svg) disc(center f64.Vec2, radius float64, c color.Color) {
+	fmt.Fprintf(s, `<circle cx="%.2f" cy="%.2f" r="%.2f" fill="%s"/>`+"\n", center[0], center[1], radius, hex(c))
+}
+
+func (s *svg) text(at f64.Vec2, text string, c color.Color) {
+	fmt.Fprintf(s, `<text x="%.2f" y="%.2f" fill="%s" font-family="monospace" font-size="12">`, at[0], at[1], hex(c))
+	_ = xml.EscapeText(s, []byte(text))
+	s.WriteString("</text>\n")
+}
+
+// hex color of c ignoring alpha.
+func hex(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
@@ -0,0 +1,175 @@
+package porkchop
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/wafer-bw/gorbit/epoch"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	marginLeft   = 80 // pixels left of the plot area for arrival dates
+	marginBottom = 36 // pixels below the plot area for departure dates
+	marginTop    = 12 // pixels above the plot area
+	marginRight  = 12 // pixels right of the plot area
+	levels       = 10 // number of contour levels
+)
+
+// colormap stops from the lowest to the highest value.
+var colormap = []color.RGBA{
+	{48, 18, 160, 255},
+	{30, 130, 230, 255},
+	{40, 200, 120, 255},
+	{240, 220, 40, 255},
+	{220, 40, 30, 255},
+}
+
+// Image of values, such as C3 or DeltaV of the grid, with departure dates
+// along the x axis and arrival dates up the y axis. Values between the
+// smallest and limit are colored from blue to red with contour lines at
+// evenly spaced levels and the smallest value is marked. Larger values and
+// cells without a transfer are left black. If limit is not positive the
+// largest value is used.
+//
+// values must have a row for every departure and a column for every
+// arrival like the matrices of the grid, otherwise the image is left
+// black.
+//
+// Encode the image with image/png to write a PNG.
+func (g *Grid) Image(values [][]float64, limit float64, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	area := image.Rect(marginLeft, marginTop, width-marginRight, height-marginBottom)
+	if area.Empty() || len(g.Departures) < 2 || len(g.Arrivals) < 2 || !g.fits(values) {
+		return img
+	}
+
+	lo, top := math.Inf(1), math.Inf(-1)
+	for _, row := range values {
+		for _, v := range row {
+			if !math.IsNaN(v) {
+				lo, top = math.Min(lo, v), math.Max(top, v)
+			}
+		}
+	}
+	hi := limit
+	if hi <= 0 {
+		hi = top
+	}
+	if !(hi > lo) {
+		hi = lo + 1
+	}
+
+	// level of every pixel of the plot area, -1 where there is no value.
+	w, h := area.Dx(), area.Dy()
+	level := make([]float64, w*h)
+	for py := 0; py < h; py++ {
+		for px := 0; px < w; px++ {
+			x := float64(px) / float64(w-1) * float64(len(g.Departures)-1)
+			y := float64(h-1-py) / float64(h-1) * float64(len(g.Arrivals)-1)
+			t := (bilinear(values, x, y) - lo) / (hi - lo)
+			if math.IsNaN(t) || t > 1 {
+				t = -1
+			}
+			level[py*w+px] = t
+		}
+	}
+
+	contour := color.RGBA{0, 0, 0, 255}
+	for py := 0; py < h; py++ {
+		for px := 0; px < w; px++ {
+			t := level[py*w+px]
+			if t < 0 {
+				continue
+			}
+			c := colorAt(t)
+			band := math.Floor(t * levels)
+			if (px+1 < w && level[py*w+px+1] >= 0 && math.Floor(level[py*w+px+1]*levels) != band) ||
+				(py+1 < h && level[(py+1)*w+px] >= 0 && math.Floor(level[(py+1)*w+px]*levels) != band) {
+				c = contour
+			}
+			img.SetRGBA(area.Min.X+px, area.Min.Y+py, c)
+		}
+	}
+
+	if i, j, ok := minimum(values); ok {
+		px := area.Min.X + int(math.Round(float64(i)/float64(len(g.Departures)-1)*float64(w-1)))
+		py := area.Min.Y + h - 1 - int(math.Round(float64(j)/float64(len(g.Arrivals)-1)*float64(h-1)))
+		for d := -4; d <= 4; d++ {
+			img.Set(px+d, py, color.White)
+			img.Set(px, py+d, color.White)
+		}
+	}
+
+	frame := color.Gray{160}
+	for x := area.Min.X - 1; x <= area.Max.X; x++ {
+		img.Set(x, area.Min.Y-1, frame)
+		img.Set(x, area.Max.Y, frame)
+	}
+	for y := area.Min.Y - 1; y <= area.Max.Y; y++ {
+		img.Set(area.Min.X-1, y, frame)
+		img.Set(area.Max.X, y, frame)
+	}
+
+	first, last := g.Departures[0], g.Departures[len(g.Departures)-1]
+	text(img, area.Min.X, area.Max.Y+14, date(first), frame)
+	text(img, area.Max.X-70, area.Max.Y+14, date(last), frame)
+	text(img, area.Min.X+w/2-28, area.Max.Y+30, "departure", color.White)
+	first, last = g.Arrivals[0], g.Arrivals[len(g.Arrivals)-1]
+	text(img, 4, area.Max.Y, date(first), frame)
+	text(img, 4, area.Min.Y+10, date(last), frame)
+	text(img, 4, area.Min.Y+h/2, "arrival", color.White)
+	return img
+}
+
+// fits is true if m has a row for every departure and a column for every
+// arrival.
+func (g *Grid) fits(m [][]float64) bool {
+	if len(m) != len(g.Departures) {
+		return false
+	}
+	for _, row := range m {
+		if len(row) != len(g.Arrivals) {
+			return false
+		}
+	}
+	return true
+}
+
+// bilinear interpolation of m at fractional row x and column y which is
+// NaN if any of the surrounding cells is.
+func bilinear(m [][]float64, x, y float64) float64 {
+	i := int(math.Min(x, float64(len(m)-2)))
+	j := int(math.Min(y, float64(len(m[0])-2)))
+	fx, fy := x-float64(i), y-float64(j)
+	return (1-fx)*(1-fy)*m[i][j] + fx*(1-fy)*m[i+1][j] + (1-fx)*fy*m[i][j+1] + fx*fy*m[i+1][j+1]
+}
+
+// colorAt t in [0, 1] along the colormap.
+func colorAt(t float64) color.RGBA {
+	x := t * float64(len(colormap)-1)
+	k := int(math.Min(x, float64(len(colormap)-2)))
+	f := x - float64(k)
+	a, b := colormap[k], colormap[k+1]
+	mix := func(p, q uint8) uint8 { return uint8(float64(p) + f*(float64(q)-float64(p)) + 0.5) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
+
+func date(jd float64) string {
+	return epoch.Time(jd).Format("2006-01-02")
+}
+
+func text(img *image.RGBA, x, y int, s string, c color.Color) {
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
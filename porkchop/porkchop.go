@@ -0,0 +1,142 @@
+// Package porkchop searches departure and arrival dates for
+// interplanetary transfers and renders the results as porkchop plots.
+package porkchop
+
+import (
+	"math"
+
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/ephemeris"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+// Transfer between two planets.
+//
+// From:            departure planet,
+// To:              arrival planet,
+// DepartureRadius: radius of the circular parking orbit at departure (m),
+// ArrivalRadius:   radius of the circular orbit captured into        (m).
+//
+// A radius of 0 counts only the hyperbolic excess velocity towards the
+// total delta-v, as for a launch directly onto the transfer or a flyby.
+type Transfer struct {
+	From            ephemeris.Planet
+	To              ephemeris.Planet
+	DepartureRadius float64
+	ArrivalRadius   float64
+}
+
+// Grid of transfers indexed by departure then arrival date.
+//
+// Departures:    departure dates                             (Julian Date),
+// Arrivals:      arrival dates                               (Julian Date),
+// C3:            characteristic energy at departure          (m^2/s^2),
+// VInfDeparture: hyperbolic excess velocity at departure     (m/s),
+// VInfArrival:   hyperbolic excess velocity at arrival       (m/s),
+// DeltaV:        total delta-v of departure and arrival burns (m/s).
+//
+// Cells without a transfer, such as arrivals before departures, are NaN.
+type Grid struct {
+	Departures    []float64
+	Arrivals      []float64
+	C3            [][]float64
+	VInfDeparture [][]float64
+	VInfArrival   [][]float64
+	DeltaV        [][]float64
+}
+
+// Dates from start to end inclusive every step days. Dates is empty
+// unless step > 0 and there are fewer than math.MaxInt32 steps from start
+// to end, as for ephemeris.Tabulate.
+func Dates(start, end, step float64) []float64 {
+	dates := []float64{}
+	if n := (end - start) / step; !(step > 0) || !(n < math.MaxInt32) {
+		return dates
+	}
+	for k := 0; ; k++ {
+		jd := start + float64(k)*step
+		if jd > end {
+			return dates
+		}
+		dates = append(dates, jd)
+	}
+}
+
+// Search every pair of departure and arrival dates (Julian Date) for the
+// prograde single revolution Lambert transfer between the planets. See
+// ephemeris.StateVectors and gravity.Lambert for more details.
+func Search(tr Transfer, departures, arrivals []float64) *Grid {
+	g := &Grid{
+		Departures:    departures,
+		Arrivals:      arrivals,
+		C3:            matrix(len(departures), len(arrivals)),
+		VInfDeparture: matrix(len(departures), len(arrivals)),
+		VInfArrival:   matrix(len(departures), len(arrivals)),
+		DeltaV:        matrix(len(departures), len(arrivals)),
+	}
+	mu := bodies.Sun.GM
+	r1 := make([]f64.Vec3, len(departures))
+	vp1 := make([]f64.Vec3, len(departures))
+	for i, dep := range departures {
+		r1[i], vp1[i] = ephemeris.StateVectors(tr.From, dep)
+	}
+	for j, arr := range arrivals {
+		r2, vp2 := ephemeris.StateVectors(tr.To, arr)
+		for i, dep := range departures {
+			v1, v2, err := gravity.Lambert(r1[i], r2, (arr-dep)*86400, mu, true)
+			if err != nil {
+				continue
+			}
+			vinf1 := vec3.Magnitude(vec3.Sub(v1, vp1[i]))
+			vinf2 := vec3.Magnitude(vec3.Sub(v2, vp2))
+			g.C3[i][j] = vinf1 * vinf1
+			g.VInfDeparture[i][j] = vinf1
+			g.VInfArrival[i][j] = vinf2
+			g.DeltaV[i][j] = burn(vinf1, tr.From.Body().GM, tr.DepartureRadius) +
+				burn(vinf2, tr.To.Body().GM, tr.ArrivalRadius)
+		}
+	}
+	return g
+}
+
+// Best departure and arrival indices of the transfer with the least total
+// delta-v. ok is false if the grid has no transfers.
+func (g *Grid) Best() (departure, arrival int, ok bool) {
+	return minimum(g.DeltaV)
+}
+
+// minimum of m returned as the row and column of the smallest value
+// ignoring NaN. ok is false if every value is NaN.
+func minimum(m [][]float64) (row, col int, ok bool) {
+	best := math.Inf(1)
+	for i, r := range m {
+		for j, v := range r {
+			if v < best {
+				best, row, col, ok = v, i, j, true
+			}
+		}
+	}
+	return row, col, ok
+}
+
+// burn between a circular orbit of radius r and a hyperbola with excess
+// velocity vinf, or vinf itself when r is 0.
+func burn(vinf, mu, r float64) float64 {
+	if r == 0 {
+		return vinf
+	}
+	return math.Sqrt(vinf*vinf+2*mu/r) - math.Sqrt(mu/r)
+}
+
+func matrix(rows, cols int) [][]float64 {
+	m := make([][]float64, rows)
+	for i := range m {
+		m[i] = make([]float64, cols)
+		for j := range m[i] {
+			m[i][j] = math.NaN()
+		}
+	}
+	return m
+}
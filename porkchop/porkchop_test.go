@@ -0,0 +1,129 @@
+package porkchop_test
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/ephemeris"
+	"github.com/wafer-bw/gorbit/epoch"
+	"github.com/wafer-bw/gorbit/porkchop"
+)
+
+func jd(year int, month time.Month, day int) float64 {
+	return epoch.JulianDate(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// search the 2005 earth to mars window.
+func search(tr porkchop.Transfer) *porkchop.Grid {
+	departures := porkchop.Dates(jd(2005, time.June, 20), jd(2005, time.November, 7), 4)
+	arrivals := porkchop.Dates(jd(2005, time.December, 1), jd(2007, time.February, 24), 8)
+	return porkchop.Search(tr, departures, arrivals)
+}
+
+func TestDates(t *testing.T) {
+	t.Run("succeed in including the end date", func(t *testing.T) {
+		require.Equal(t, []float64{10, 12.5, 15}, porkchop.Dates(10, 15, 2.5))
+	})
+	t.Run("fail to make dates for a non positive step or endless range", func(t *testing.T) {
+		require.Empty(t, porkchop.Dates(10, 15, 0))
+		require.Empty(t, porkchop.Dates(10, 15, -1))
+		require.Empty(t, porkchop.Dates(10, math.Inf(1), 1))
+		require.Empty(t, porkchop.Dates(15, 10, 1))
+	})
+}
+
+func TestSearch(t *testing.T) {
+	g := search(porkchop.Transfer{From: ephemeris.Earth, To: ephemeris.Mars})
+	t.Run("succeed in finding the 2005 mars window", func(t *testing.T) {
+		i, j, ok := g.Best()
+		require.True(t, ok)
+		dep := epoch.Time(g.Departures[i])
+		require.Equal(t, "2005-08", dep.Format("2006-01"))
+		require.Greater(t, g.C3[i][j], 14e6)
+		require.Less(t, g.C3[i][j], 20e6)
+		require.Equal(t, fmt.Sprintf("%.6f", g.VInfDeparture[i][j]+g.VInfArrival[i][j]), fmt.Sprintf("%.6f", g.DeltaV[i][j]))
+		require.Equal(t, fmt.Sprintf("%.6f", g.VInfDeparture[i][j]*g.VInfDeparture[i][j]), fmt.Sprintf("%.6f", g.C3[i][j]))
+	})
+	t.Run("succeed in shaping the grid by departure then arrival", func(t *testing.T) {
+		require.Len(t, g.C3, len(g.Departures))
+		require.Len(t, g.C3[0], len(g.Arrivals))
+	})
+	t.Run("succeed in counting parking orbit burns", func(t *testing.T) {
+		r1, r2 := bodies.Earth.Radius+200e3, bodies.Mars.Radius+400e3
+		parked := search(porkchop.Transfer{From: ephemeris.Earth, To: ephemeris.Mars, DepartureRadius: r1, ArrivalRadius: r2})
+		i, j, _ := g.Best()
+		mu1, mu2 := bodies.Earth.GM, bodies.Mars.GM
+		dv1 := math.Sqrt(g.C3[i][j]+2*mu1/r1) - math.Sqrt(mu1/r1)
+		vinf := g.VInfArrival[i][j]
+		dv2 := math.Sqrt(vinf*vinf+2*mu2/r2) - math.Sqrt(mu2/r2)
+		require.Equal(t, fmt.Sprintf("%.6f", dv1+dv2), fmt.Sprintf("%.6f", parked.DeltaV[i][j]))
+	})
+	t.Run("succeed in leaving arrivals before departures empty", func(t *testing.T) {
+		g := porkchop.Search(porkchop.Transfer{From: ephemeris.Earth, To: ephemeris.Mars}, []float64{jd(2005, time.August, 1)}, []float64{jd(2005, time.July, 1)})
+		require.True(t, math.IsNaN(g.C3[0][0]))
+		_, _, ok := g.Best()
+		require.False(t, ok)
+	})
+}
+
+func TestImage(t *testing.T) {
+	g := search(porkchop.Transfer{From: ephemeris.Earth, To: ephemeris.Mars})
+	t.Run("succeed in rendering a PNG", func(t *testing.T) {
+		img := g.Image(g.C3, 50e6, 400, 300)
+		require.Equal(t, 400, img.Bounds().Dx())
+		require.Equal(t, 300, img.Bounds().Dy())
+		buf := bytes.Buffer{}
+		require.NoError(t, png.Encode(&buf, img))
+		require.Greater(t, buf.Len(), 1000)
+	})
+	t.Run("succeed in coloring values and leaving missing ones black", func(t *testing.T) {
+		img := g.Image(g.DeltaV, 0, 400, 300)
+		colored, black := 0, 0
+		for y := 12; y < 264; y++ {
+			for x := 80; x < 388; x++ {
+				c := img.RGBAAt(x, y)
+				if c.R == 0 && c.G == 0 && c.B == 0 {
+					black++
+				} else {
+					colored++
+				}
+			}
+		}
+		require.Greater(t, colored, black)
+		require.Greater(t, black, 0)
+	})
+	t.Run("succeed in marking the smallest of the rendered values", func(t *testing.T) {
+		white := color.RGBA{255, 255, 255, 255}
+		g := &porkchop.Grid{
+			Departures: []float64{0, 1, 2},
+			Arrivals:   []float64{0, 1, 2},
+			C3:         [][]float64{{9, 8, 7}, {6, 5, 4}, {3, 2, 1}},
+			DeltaV:     [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}},
+		}
+		// the plot area of a 400x300 image spans x 80-387 and y 12-263 with
+		// the first departure and arrival in the bottom left corner.
+		img := g.Image(g.C3, 0, 400, 300)
+		require.Equal(t, white, img.RGBAAt(385, 12))
+		require.NotEqual(t, white, img.RGBAAt(82, 263))
+		img = g.Image(g.DeltaV, 0, 400, 300)
+		require.Equal(t, white, img.RGBAAt(82, 263))
+		require.NotEqual(t, white, img.RGBAAt(385, 12))
+	})
+	t.Run("succeed in leaving the image black for values of the wrong shape", func(t *testing.T) {
+		short := make([][]float64, len(g.C3))
+		for i := range short {
+			short[i] = g.C3[i][1:]
+		}
+		for _, values := range [][][]float64{nil, g.C3[1:], short} {
+			img := g.Image(values, 0, 400, 300)
+			require.Equal(t, color.RGBA{0, 0, 0, 255}, img.RGBAAt(200, 150))
+		}
+	})
+}
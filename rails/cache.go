@@ -0,0 +1,54 @@
+package rails
+
+import (
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+// Cache of the absolute states of a System for the most recent time, or
+// tick, requested. Every body is computed at most once per tick and each
+// ancestor is shared by all of its descendants.
+//
+// A Cache is not safe for concurrent use.
+type Cache struct {
+	system System
+	t      float64
+	done   []bool
+	r, v   []f64.Vec3
+}
+
+// NewCache of the absolute states of system which is validated first.
+// The system must not be modified while the cache is in use.
+func NewCache(system System) (*Cache, error) {
+	if err := system.Validate(); err != nil {
+		return nil, err
+	}
+	return &Cache{
+		system: system,
+		done:   make([]bool, len(system)),
+		r:      make([]f64.Vec3, len(system)),
+		v:      make([]f64.Vec3, len(system)),
+	}, nil
+}
+
+// Absolute state of body k t seconds after epoch. Requesting a different
+// t starts a new tick and discards the states of the previous one. No
+// memory is allocated. See System.Absolute for more details.
+func (c *Cache) Absolute(k int, t float64) (f64.Vec3, f64.Vec3) {
+	if t != c.t {
+		c.t = t
+		for j := range c.done {
+			c.done[j] = false
+		}
+	}
+	if c.done[k] {
+		return c.r[k], c.v[k]
+	}
+	r, v := c.system.State(k, t)
+	if p := c.system[k].Parent; p >= 0 {
+		rp, vp := c.Absolute(p, t)
+		r, v = vec3.Add(r, rp), vec3.Add(v, vp)
+	}
+	c.r[k], c.v[k], c.done[k] = r, v, true
+	return r, v
+}
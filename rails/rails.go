@@ -0,0 +1,121 @@
+// Package rails models a hierarchy of bodies on-rails where every body
+// follows fixed Keplerian Orbital Elements around its parent. Following
+// the convention of gravity.OrbitalElements the mass of each child is
+// ignored so the elements use the standard gravitational parameter of the
+// parent alone.
+//
+// Package patched propagates spacecraft through a rails.System with
+// patched conics.
+package rails
+
+import (
+	"errors"
+	"math"
+
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+var ErrParent = errors.New("rails: parent must be -1 or the index of an earlier body")
+
+// Body of a System on-rails around its parent.
+//
+// Name:   name of the body,
+// Mu:     standard gravitational parameter                  (m^3/s^2),
+// SOI:    sphere of influence radius, 0 to use LaplaceSOI   (m),
+// Parent: index of the parent body in the System, -1 for the root,
+// A:      semi-major axis                                   (m),
+// E:      eccentricity                                      (0-1),
+// W:      argument of periapsis                             (rad),
+// LAN:    longitude of ascending node                       (rad),
+// I:      inclination                                       (rad),
+// M0:     mean anomaly at epoch                             (rad).
+//
+// The elements describe the orbit around the parent and are ignored for
+// the root which sits at the origin.
+type Body struct {
+	Name   string
+	Mu     float64
+	SOI    float64
+	Parent int
+	A      float64
+	E      float64
+	W      float64
+	LAN    float64
+	I      float64
+	M0     float64
+}
+
+// System of bodies where every body except the roots orbits its parent.
+// Parents must appear before their children.
+type System []Body
+
+// Validate that every parent is -1 or the index of an earlier body.
+func (s System) Validate() error {
+	for k, b := range s {
+		if b.Parent < -1 || b.Parent >= k {
+			return ErrParent
+		}
+	}
+	return nil
+}
+
+// Index of the first body named name or -1 if there is none.
+func (s System) Index(name string) int {
+	for k, b := range s {
+		if b.Name == name {
+			return k
+		}
+	}
+	return -1
+}
+
+// Children of body k in System order.
+func (s System) Children(k int) []int {
+	children := []int{}
+	for j, b := range s {
+		if b.Parent == k && j != k {
+			children = append(children, j)
+		}
+	}
+	return children
+}
+
+// State of body k relative to its parent t seconds after epoch. Roots
+// are always at rest at the origin.
+func (s System) State(k int, t float64) (f64.Vec3, f64.Vec3) {
+	b := s[k]
+	if b.Parent < 0 {
+		return f64.Vec3{}, f64.Vec3{}
+	}
+	return gravity.StateVectorsMu(b.A, b.E, b.W, b.LAN, b.I, b.M0, t, s[b.Parent].Mu)
+}
+
+// Absolute state of body k in the inertial frame of its root t seconds
+// after epoch found by adding the states of every ancestor.
+//
+// The System must pass Validate, otherwise a body which is its own
+// ancestor never reaches a root.
+func (s System) Absolute(k int, t float64) (f64.Vec3, f64.Vec3) {
+	r, v := f64.Vec3{}, f64.Vec3{}
+	for ; k >= 0; k = s[k].Parent {
+		rk, vk := s.State(k, t)
+		r, v = vec3.Add(r, rk), vec3.Add(v, vk)
+	}
+	return r, v
+}
+
+// SOI radius of body k (m). Body.SOI is used when set, otherwise the
+// Laplace sphere of influence is derived from the orbit around the parent.
+// Roots have an infinite sphere of influence.
+func (s System) SOI(k int) float64 {
+	b := s[k]
+	switch {
+	case b.SOI > 0:
+		return b.SOI
+	case b.Parent < 0:
+		return math.Inf(1)
+	}
+	return gravity.LaplaceSOI(b.A, s[b.Parent].Mu, b.Mu)
+}
@@ -0,0 +1,29 @@
+package rails_test
+
+import (
+	"testing"
+
+	"github.com/wafer-bw/gorbit/rails"
+)
+
+func BenchmarkAbsolute(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		t := float64(i)
+		for k := range system {
+			system.Absolute(k, t)
+		}
+	}
+}
+
+func BenchmarkCacheAbsolute(b *testing.B) {
+	c, err := rails.NewCache(system)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		t := float64(i)
+		for k := range system {
+			c.Absolute(k, t)
+		}
+	}
+}
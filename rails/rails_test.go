@@ -0,0 +1,104 @@
+package rails_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/rails"
+	"github.com/wafer-bw/gorbit/vec3"
+)
+
+const (
+	sun = iota
+	earth
+	moon
+	mars
+)
+
+var system = rails.System{
+	sun:   {Name: "Sun", Mu: bodies.Sun.GM, Parent: -1},
+	earth: {Name: "Earth", Mu: bodies.Earth.GM, Parent: sun, A: gravity.AU, E: 0.0167, M0: 1},
+	moon:  {Name: "Moon", Mu: bodies.Moon.GM, Parent: earth, A: 384400e3, E: 0.0549, I: 0.09, M0: 2, SOI: bodies.Moon.SOI},
+	mars:  {Name: "Mars", Mu: bodies.Mars.GM, Parent: sun, A: 1.524 * gravity.AU, E: 0.0934, I: 0.032},
+}
+
+func TestSystem(t *testing.T) {
+	t.Run("succeed in validating a system", func(t *testing.T) {
+		require.NoError(t, system.Validate())
+	})
+	t.Run("fail to validate a parent after its child", func(t *testing.T) {
+		s := rails.System{{Name: "a", Parent: 1}, {Name: "b", Parent: -1}}
+		require.ErrorIs(t, s.Validate(), rails.ErrParent)
+	})
+	t.Run("succeed in finding bodies by name", func(t *testing.T) {
+		require.Equal(t, moon, system.Index("Moon"))
+		require.Equal(t, -1, system.Index("Pluto"))
+	})
+	t.Run("succeed in listing children", func(t *testing.T) {
+		require.Equal(t, []int{earth, mars}, system.Children(sun))
+		require.Equal(t, []int{}, system.Children(moon))
+	})
+}
+
+func TestAbsolute(t *testing.T) {
+	t.Run("succeed in keeping roots at the origin", func(t *testing.T) {
+		r, v := system.Absolute(sun, 1000)
+		require.Equal(t, 0.0, vec3.Magnitude(r))
+		require.Equal(t, 0.0, vec3.Magnitude(v))
+	})
+	t.Run("succeed in composing states up the tree", func(t *testing.T) {
+		tt := 86400 * 12.5
+		re, ve := system.State(earth, tt)
+		rm, vm := system.State(moon, tt)
+		r, v := system.Absolute(moon, tt)
+		require.Equal(t, vec3.Add(re, rm), r)
+		require.Equal(t, vec3.Add(ve, vm), v)
+		require.Equal(t, "1.0", fmt.Sprintf("%.1f", vec3.Magnitude(r)/gravity.AU))
+	})
+}
+
+func TestCache(t *testing.T) {
+	t.Run("succeed in matching System.Absolute", func(t *testing.T) {
+		c, err := rails.NewCache(system)
+		require.NoError(t, err)
+		for _, tt := range []float64{0, 0, 3600, 86400, 3600} {
+			for _, k := range []int{moon, earth, sun, mars, moon} {
+				wantR, wantV := system.Absolute(k, tt)
+				r, v := c.Absolute(k, tt)
+				require.Equal(t, wantR, r)
+				require.Equal(t, wantV, v)
+			}
+		}
+	})
+	t.Run("fail to cache a body which is its own parent", func(t *testing.T) {
+		_, err := rails.NewCache(rails.System{{Name: "a", Parent: 0}})
+		require.ErrorIs(t, err, rails.ErrParent)
+	})
+	t.Run("succeed without allocating", func(t *testing.T) {
+		c, err := rails.NewCache(system)
+		require.NoError(t, err)
+		tt := 0.0
+		allocs := testing.AllocsPerRun(100, func() {
+			tt += 60
+			c.Absolute(moon, tt)
+			c.Absolute(mars, tt)
+		})
+		require.Equal(t, 0.0, allocs)
+	})
+}
+
+func TestSOI(t *testing.T) {
+	t.Run("succeed in using the configured sphere of influence", func(t *testing.T) {
+		require.Equal(t, bodies.Moon.SOI, system.SOI(moon))
+	})
+	t.Run("succeed in deriving the laplace sphere of influence", func(t *testing.T) {
+		require.Equal(t, "9.25e+08", fmt.Sprintf("%.2e", system.SOI(earth)))
+	})
+	t.Run("succeed in giving roots an infinite sphere of influence", func(t *testing.T) {
+		require.True(t, math.IsInf(system.SOI(sun), 1))
+	})
+}
@@ -0,0 +1,144 @@
+// Package relative models the motion of a chaser relative to a target on
+// a circular orbit using the Clohessy-Wiltshire (Hill) equations.
+//
+// Relative states are expressed in the rotating local-vertical
+// local-horizontal (LVLH) frame of the target with x radial, y along-track
+// and z cross-track, which is the RTN frame of the frames package.
+package relative
+
+import (
+	"errors"
+	"math"
+
+	"github.com/wafer-bw/gorbit/frames"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+var ErrSingular = errors.New("relative: no unique rendezvous for the transfer time")
+
+// ToLVLH relative state of the chaser in the LVLH frame of the target.
+//
+// accepts:
+// rc: position of the chaser relative to primary body (m),
+// vc: velocity of the chaser relative to primary body (m/s),
+// rt: position of the target relative to primary body (m),
+// vt: velocity of the target relative to primary body (m/s).
+//
+// returns:
+// dr: relative position in the LVLH frame (m),
+// dv: relative velocity in the LVLH frame (m/s).
+//
+// See frames.InertialToRTNState for more details.
+func ToLVLH(rc, vc, rt, vt f64.Vec3) (dr, dv f64.Vec3) {
+	return frames.InertialToRTNState(vec3.Sub(rc, rt), vec3.Sub(vc, vt), rt, vt)
+}
+
+// FromLVLH inertial state of the chaser from its relative state in the
+// LVLH frame of the target. This is the inverse of ToLVLH.
+func FromLVLH(dr, dv, rt, vt f64.Vec3) (rc, vc f64.Vec3) {
+	r, v := frames.RTNToInertialState(dr, dv, rt, vt)
+	return vec3.Add(rt, r), vec3.Add(vt, v)
+}
+
+// MeanMotion of a circular orbit (rad/s).
+//
+// a:  radius of the orbit                (m),
+// mu: standard gravitational parameter   (m^3/s^2).
+func MeanMotion(a, mu float64) float64 {
+	return math.Sqrt(mu / (a * a * a))
+}
+
+// CW relative state t seconds after (dr, dv) using the closed form
+// solution of the Clohessy-Wiltshire equations.
+//
+// accepts:
+// dr: relative position in the LVLH frame (m),
+// dv: relative velocity in the LVLH frame (m/s),
+// n:  mean motion of the target           (rad/s),
+// t:  time to propagate                   (s).
+//
+// The equations assume a circular target orbit and a separation much
+// smaller than its radius.
+//
+// https://en.wikipedia.org/wiki/Clohessy%E2%80%93Wiltshire_equations
+func CW(dr, dv f64.Vec3, n, t float64) (f64.Vec3, f64.Vec3) {
+	rr, rv, vr, vv := stm(n, t)
+	return vec3.Add(vec3.MulMat(rr, dr), vec3.MulMat(rv, dv)), vec3.Add(vec3.MulMat(vr, dr), vec3.MulMat(vv, dv))
+}
+
+// Rendezvous two impulse transfer from (dr, dv) to the target in t
+// seconds.
+//
+// accepts:
+// dr: relative position in the LVLH frame (m),
+// dv: relative velocity in the LVLH frame (m/s),
+// n:  mean motion of the target           (rad/s),
+// t:  transfer time                       (s).
+//
+// returns:
+// dv1: delta-v of the first burn in the LVLH frame  (m/s),
+// dv2: delta-v of the arrival burn in the LVLH frame (m/s).
+//
+// ErrSingular is returned for transfer times, such as whole orbits, where
+// the along-track and radial motion cannot be controlled independently.
+// See CW for more details.
+func Rendezvous(dr, dv f64.Vec3, n, t float64) (dv1, dv2 f64.Vec3, err error) {
+	rr, rv, vr, vv := stm(n, t)
+	inv, ok := inverse(rv)
+	if !ok {
+		return f64.Vec3{}, f64.Vec3{}, ErrSingular
+	}
+	v0 := vec3.MulScalar(vec3.MulMat(inv, vec3.MulMat(rr, dr)), -1)
+	vf := vec3.Add(vec3.MulMat(vr, dr), vec3.MulMat(vv, v0))
+	return vec3.Sub(v0, dv), vec3.MulScalar(vf, -1), nil
+}
+
+// stm blocks of the Clohessy-Wiltshire state transition matrix.
+func stm(n, t float64) (rr, rv, vr, vv f64.Mat3) {
+	s, c := math.Sincos(n * t)
+	nt := n * t
+	rr = f64.Mat3{
+		4 - 3*c, 0, 0,
+		6 * (s - nt), 1, 0,
+		0, 0, c,
+	}
+	rv = f64.Mat3{
+		s / n, 2 * (1 - c) / n, 0,
+		-2 * (1 - c) / n, (4*s - 3*nt) / n, 0,
+		0, 0, s / n,
+	}
+	vr = f64.Mat3{
+		3 * n * s, 0, 0,
+		-6 * n * (1 - c), 0, 0,
+		0, 0, -n * s,
+	}
+	vv = f64.Mat3{
+		c, 2 * s, 0,
+		-2 * s, 4*c - 3, 0,
+		0, 0, c,
+	}
+	return
+}
+
+// inverse of m or false if it is singular.
+func inverse(m f64.Mat3) (f64.Mat3, bool) {
+	c := f64.Mat3{
+		m[4]*m[8] - m[5]*m[7], m[2]*m[7] - m[1]*m[8], m[1]*m[5] - m[2]*m[4],
+		m[5]*m[6] - m[3]*m[8], m[0]*m[8] - m[2]*m[6], m[2]*m[3] - m[0]*m[5],
+		m[3]*m[7] - m[4]*m[6], m[1]*m[6] - m[0]*m[7], m[0]*m[4] - m[1]*m[3],
+	}
+	det := m[0]*c[0] + m[1]*c[3] + m[2]*c[6]
+	// Compare against the largest element so the test is scale free.
+	largest := 0.0
+	for _, x := range m {
+		largest = math.Max(largest, math.Abs(x))
+	}
+	if !(math.Abs(det) > 1e-9*largest*largest*largest) {
+		return f64.Mat3{}, false
+	}
+	for k := range c {
+		c[k] /= det
+	}
+	return c, true
+}
@@ -0,0 +1,90 @@
+package relative_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/bodies"
+	"github.com/wafer-bw/gorbit/gravity"
+	"github.com/wafer-bw/gorbit/relative"
+	"github.com/wafer-bw/gorbit/vec3"
+	"golang.org/x/image/math/f64"
+)
+
+var (
+	mu     = bodies.Earth.GM
+	a      = 6778e3
+	n      = relative.MeanMotion(a, mu)
+	period = gravity.PeriodMu(a, mu)
+)
+
+// target on a circular inclined orbit at time t.
+func target(t float64) (f64.Vec3, f64.Vec3) {
+	return gravity.StateVectorsMu(a, 0, 0, 0.4, 0.9, 0, t, mu)
+}
+
+func TestLVLH(t *testing.T) {
+	rt, vt := target(0)
+	t.Run("succeed in a round trip", func(t *testing.T) {
+		rc, vc := vec3.Add(rt, f64.Vec3{100, -200, 50}), vec3.Add(vt, f64.Vec3{0.1, 0.2, -0.3})
+		dr, dv := relative.ToLVLH(rc, vc, rt, vt)
+		r, v := relative.FromLVLH(dr, dv, rt, vt)
+		require.Less(t, vec3.Magnitude(vec3.Sub(r, rc)), 1e-6)
+		require.Less(t, vec3.Magnitude(vec3.Sub(v, vc)), 1e-9)
+	})
+	t.Run("succeed in placing a chaser above the target on the x axis", func(t *testing.T) {
+		rc := vec3.Add(rt, vec3.MulScalar(vec3.Normalize(rt), 100))
+		dr, _ := relative.ToLVLH(rc, vt, rt, vt)
+		require.Equal(t, "100.000000", fmt.Sprintf("%.6f", dr[0]))
+		require.Less(t, math.Hypot(dr[1], dr[2]), 1e-6)
+	})
+}
+
+func TestCW(t *testing.T) {
+	t.Run("succeed in matching two body motion for small separations", func(t *testing.T) {
+		rt, vt := target(0)
+		dr0, dv0 := f64.Vec3{100, -500, 200}, f64.Vec3{0.05, -0.1, 0.02}
+		rc, vc := relative.FromLVLH(dr0, dv0, rt, vt)
+		for _, dt := range []float64{60, 600, period / 2} {
+			rc1, vc1 := gravity.Propagate(rc, vc, dt, mu)
+			rt1, vt1 := target(dt)
+			want, _ := relative.ToLVLH(rc1, vc1, rt1, vt1)
+			got, _ := relative.CW(dr0, dv0, n, dt)
+			require.Less(t, vec3.Magnitude(vec3.Sub(got, want)), 1.0, dt)
+		}
+	})
+	t.Run("succeed in drifting ahead when below the target", func(t *testing.T) {
+		// a lower orbit is faster so the chaser moves ahead along-track.
+		dr, _ := relative.CW(f64.Vec3{-100, 0, 0}, f64.Vec3{0, 1.5 * n * 100, 0}, n, period)
+		require.Equal(t, fmt.Sprintf("%.3f", 3*math.Pi*100), fmt.Sprintf("%.3f", dr[1]))
+		require.Equal(t, "-100.000", fmt.Sprintf("%.3f", dr[0]))
+	})
+}
+
+func TestRendezvous(t *testing.T) {
+	dr0, dv0 := f64.Vec3{200, -1000, 100}, f64.Vec3{0, 0.3, 0}
+	t.Run("succeed in arriving at the target", func(t *testing.T) {
+		tof := period / 3
+		dv1, dv2, err := relative.Rendezvous(dr0, dv0, n, tof)
+		require.NoError(t, err)
+		dr, dv := relative.CW(dr0, vec3.Add(dv0, dv1), n, tof)
+		require.Less(t, vec3.Magnitude(dr), 1e-6)
+		require.Less(t, vec3.Magnitude(vec3.Add(dv, dv2)), 1e-9)
+	})
+	t.Run("succeed in arriving close to the target in two body motion", func(t *testing.T) {
+		tof := period / 3
+		dv1, _, err := relative.Rendezvous(dr0, dv0, n, tof)
+		require.NoError(t, err)
+		rt, vt := target(0)
+		rc, vc := relative.FromLVLH(dr0, vec3.Add(dv0, dv1), rt, vt)
+		rc, _ = gravity.Propagate(rc, vc, tof, mu)
+		rt, _ = target(tof)
+		require.Less(t, vec3.Magnitude(vec3.Sub(rc, rt)), 5.0)
+	})
+	t.Run("fail for a whole orbit transfer", func(t *testing.T) {
+		_, _, err := relative.Rendezvous(dr0, dv0, n, period)
+		require.ErrorIs(t, err, relative.ErrSingular)
+	})
+}
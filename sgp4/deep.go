@@ -0,0 +1,499 @@
+package sgp4
+
+import "math"
+
+// Deep space (SDP4) lunar-solar and resonance terms ported from Vallado's
+// reference implementation.
+
+// dscomResult intermediate values from dscom used by dsinit.
+type dscomResult struct {
+	sinim, cosim, emsq                   float64
+	s1, s2, s3, s4, s5                   float64
+	ss1, ss2, ss3, ss4, ss5              float64
+	sz1, sz3, sz11, sz13, sz21, sz23     float64
+	sz31, sz33                           float64
+	z1, z3, z11, z13, z21, z23, z31, z33 float64
+	em, nm                               float64
+}
+
+// dscom deep space common terms, sets the lunar-solar periodic
+// coefficients on s.
+func (s *Satellite) dscom(tc, ep, argpp, inclp, nodep, np float64) dscomResult {
+	const (
+		zes    = 0.01675
+		zel    = 0.05490
+		c1ss   = 2.9864797e-6
+		c1l    = 4.7968065e-7
+		zsinis = 0.39785416
+		zcosis = 0.91744867
+		zcosgs = 0.1945905
+		zsings = -0.98088458
+	)
+
+	r := dscomResult{nm: np, em: ep}
+	snodm, cnodm := math.Sincos(nodep)
+	sinomm, cosomm := math.Sincos(argpp)
+	r.sinim, r.cosim = math.Sincos(inclp)
+	r.emsq = r.em * r.em
+	betasq := 1 - r.emsq
+	rtemsq := math.Sqrt(betasq)
+
+	s.peo, s.pinco, s.plo, s.pgho, s.pho = 0, 0, 0, 0, 0
+	day := s.epoch - epoch50 + 18261.5 + tc/minDay
+	xnodce := math.Mod(4.5236020-9.2422029e-4*day, twoPi)
+	stem, ctem := math.Sincos(xnodce)
+	zcosil := 0.91375164 - 0.03568096*ctem
+	zsinil := math.Sqrt(1 - zcosil*zcosil)
+	zsinhl := 0.089683511 * stem / zsinil
+	zcoshl := math.Sqrt(1 - zsinhl*zsinhl)
+	gam := 5.8351514 + 0.0019443680*day
+	zx := 0.39785416 * stem / zsinil
+	zy := zcoshl*ctem + 0.91744867*zsinhl*stem
+	zx = math.Atan2(zx, zy)
+	zx = gam + zx - xnodce
+	zsingl, zcosgl := math.Sincos(zx)
+
+	// solar terms first then lunar terms
+	zcosg := zcosgs
+	zsing := zsings
+	zcosi := zcosis
+	zsini := zsinis
+	zcosh := cnodm
+	zsinh := snodm
+	cc := c1ss
+	xnoi := 1 / r.nm
+
+	var z12, z2, z22, z32, s6, s7 float64
+	var ss6, ss7, sz2, sz12, sz22, sz32 float64
+	for lsflg := 1; lsflg <= 2; lsflg++ {
+		a1 := zcosg*zcosh + zsing*zcosi*zsinh
+		a3 := -zsing*zcosh + zcosg*zcosi*zsinh
+		a7 := -zcosg*zsinh + zsing*zcosi*zcosh
+		a8 := zsing * zsini
+		a9 := zsing*zsinh + zcosg*zcosi*zcosh
+		a10 := zcosg * zsini
+		a2 := r.cosim*a7 + r.sinim*a8
+		a4 := r.cosim*a9 + r.sinim*a10
+		a5 := -r.sinim*a7 + r.cosim*a8
+		a6 := -r.sinim*a9 + r.cosim*a10
+
+		x1 := a1*cosomm + a2*sinomm
+		x2 := a3*cosomm + a4*sinomm
+		x3 := -a1*sinomm + a2*cosomm
+		x4 := -a3*sinomm + a4*cosomm
+		x5 := a5 * sinomm
+		x6 := a6 * sinomm
+		x7 := a5 * cosomm
+		x8 := a6 * cosomm
+
+		r.z31 = 12*x1*x1 - 3*x3*x3
+		z32 = 24*x1*x2 - 6*x3*x4
+		r.z33 = 12*x2*x2 - 3*x4*x4
+		r.z1 = 3*(a1*a1+a2*a2) + r.z31*r.emsq
+		z2 = 6*(a1*a3+a2*a4) + z32*r.emsq
+		r.z3 = 3*(a3*a3+a4*a4) + r.z33*r.emsq
+		r.z11 = -6*a1*a5 + r.emsq*(-24*x1*x7-6*x3*x5)
+		z12 = -6*(a1*a6+a3*a5) + r.emsq*(-24*(x2*x7+x1*x8)-6*(x3*x6+x4*x5))
+		r.z13 = -6*a3*a6 + r.emsq*(-24*x2*x8-6*x4*x6)
+		r.z21 = 6*a2*a5 + r.emsq*(24*x1*x5-6*x3*x7)
+		z22 = 6*(a4*a5+a2*a6) + r.emsq*(24*(x2*x5+x1*x6)-6*(x4*x7+x3*x8))
+		r.z23 = 6*a4*a6 + r.emsq*(24*x2*x6-6*x4*x8)
+		r.z1 = r.z1 + r.z1 + betasq*r.z31
+		z2 = z2 + z2 + betasq*z32
+		r.z3 = r.z3 + r.z3 + betasq*r.z33
+		r.s3 = cc * xnoi
+		r.s2 = -0.5 * r.s3 / rtemsq
+		r.s4 = r.s3 * rtemsq
+		r.s1 = -15 * r.em * r.s4
+		r.s5 = x1*x3 + x2*x4
+		s6 = x2*x3 + x1*x4
+		s7 = x2*x4 - x1*x3
+
+		if lsflg == 1 {
+			r.ss1, r.ss2, r.ss3, r.ss4, r.ss5, ss6, ss7 = r.s1, r.s2, r.s3, r.s4, r.s5, s6, s7
+			r.sz1, sz2, r.sz3 = r.z1, z2, r.z3
+			r.sz11, sz12, r.sz13 = r.z11, z12, r.z13
+			r.sz21, sz22, r.sz23 = r.z21, z22, r.z23
+			r.sz31, sz32, r.sz33 = r.z31, z32, r.z33
+			zcosg = zcosgl
+			zsing = zsingl
+			zcosi = zcosil
+			zsini = zsinil
+			zcosh = zcoshl*cnodm + zsinhl*snodm
+			zsinh = snodm*zcoshl - cnodm*zsinhl
+			cc = c1l
+		}
+	}
+
+	s.zmol = math.Mod(4.7199672+0.22997150*day-gam, twoPi)
+	s.zmos = math.Mod(6.2565837+0.017201977*day, twoPi)
+
+	// solar terms
+	s.se2 = 2 * r.ss1 * ss6
+	s.se3 = 2 * r.ss1 * ss7
+	s.si2 = 2 * r.ss2 * sz12
+	s.si3 = 2 * r.ss2 * (r.sz13 - r.sz11)
+	s.sl2 = -2 * r.ss3 * sz2
+	s.sl3 = -2 * r.ss3 * (r.sz3 - r.sz1)
+	s.sl4 = -2 * r.ss3 * (-21 - 9*r.emsq) * zes
+	s.sgh2 = 2 * r.ss4 * sz32
+	s.sgh3 = 2 * r.ss4 * (r.sz33 - r.sz31)
+	s.sgh4 = -18 * r.ss4 * zes
+	s.sh2 = -2 * r.ss2 * sz22
+	s.sh3 = -2 * r.ss2 * (r.sz23 - r.sz21)
+
+	// lunar terms
+	s.ee2 = 2 * r.s1 * s6
+	s.e3 = 2 * r.s1 * s7
+	s.xi2 = 2 * r.s2 * z12
+	s.xi3 = 2 * r.s2 * (r.z13 - r.z11)
+	s.xl2 = -2 * r.s3 * z2
+	s.xl3 = -2 * r.s3 * (r.z3 - r.z1)
+	s.xl4 = -2 * r.s3 * (-21 - 9*r.emsq) * zel
+	s.xgh2 = 2 * r.s4 * z32
+	s.xgh3 = 2 * r.s4 * (r.z33 - r.z31)
+	s.xgh4 = -18 * r.s4 * zel
+	s.xh2 = -2 * r.s2 * z22
+	s.xh3 = -2 * r.s2 * (r.z23 - r.z21)
+
+	return r
+}
+
+// dpper deep space long period periodic contributions to the mean
+// elements. When init is true the contributions are not applied.
+func (s Satellite) dpper(t float64, init bool, ep, inclp, nodep, argpp, mp float64) (float64, float64, float64, float64, float64) {
+	const (
+		zns = 1.19459e-5
+		zes = 0.01675
+		znl = 1.5835218e-4
+		zel = 0.05490
+	)
+
+	// solar terms
+	zm := s.zmos + zns*t
+	if init {
+		zm = s.zmos
+	}
+	zf := zm + 2*zes*math.Sin(zm)
+	sinzf, coszf := math.Sincos(zf)
+	f2 := 0.5*sinzf*sinzf - 0.25
+	f3 := -0.5 * sinzf * coszf
+	ses := s.se2*f2 + s.se3*f3
+	sis := s.si2*f2 + s.si3*f3
+	sls := s.sl2*f2 + s.sl3*f3 + s.sl4*sinzf
+	sghs := s.sgh2*f2 + s.sgh3*f3 + s.sgh4*sinzf
+	shs := s.sh2*f2 + s.sh3*f3
+
+	// lunar terms
+	zm = s.zmol + znl*t
+	if init {
+		zm = s.zmol
+	}
+	zf = zm + 2*zel*math.Sin(zm)
+	sinzf, coszf = math.Sincos(zf)
+	f2 = 0.5*sinzf*sinzf - 0.25
+	f3 = -0.5 * sinzf * coszf
+	sel := s.ee2*f2 + s.e3*f3
+	sil := s.xi2*f2 + s.xi3*f3
+	sll := s.xl2*f2 + s.xl3*f3 + s.xl4*sinzf
+	sghl := s.xgh2*f2 + s.xgh3*f3 + s.xgh4*sinzf
+	shll := s.xh2*f2 + s.xh3*f3
+
+	if init {
+		return ep, inclp, nodep, argpp, mp
+	}
+
+	pe := ses + sel - s.peo
+	pinc := sis + sil - s.pinco
+	pl := sls + sll - s.plo
+	pgh := sghs + sghl - s.pgho
+	ph := shs + shll - s.pho
+
+	inclp += pinc
+	ep += pe
+	sinip, cosip := math.Sincos(inclp)
+
+	if inclp >= 0.2 {
+		ph /= sinip
+		pgh -= cosip * ph
+		argpp += pgh
+		nodep += ph
+		mp += pl
+		return ep, inclp, nodep, argpp, mp
+	}
+
+	// apply periodics with lyddane modification
+	sinop, cosop := math.Sincos(nodep)
+	alfdp := sinip * sinop
+	betdp := sinip * cosop
+	dalf := ph*cosop + pinc*cosip*sinop
+	dbet := -ph*sinop + pinc*cosip*cosop
+	alfdp += dalf
+	betdp += dbet
+	nodep = math.Mod(nodep, twoPi)
+	xls := mp + argpp + cosip*nodep
+	dls := pl + pgh - pinc*nodep*sinip
+	xls += dls
+	xnoh := nodep
+	nodep = math.Atan2(alfdp, betdp)
+	if math.Abs(xnoh-nodep) > math.Pi {
+		if nodep < xnoh {
+			nodep += twoPi
+		} else {
+			nodep -= twoPi
+		}
+	}
+	mp += pl
+	argpp = xls - mp - cosip*nodep
+
+	return ep, inclp, nodep, argpp, mp
+}
+
+// dsinit deep space secular rates and resonance terms.
+func (s *Satellite) dsinit(c dscomResult, eccsq, xpidot float64) {
+	const (
+		q22    = 1.7891679e-6
+		q31    = 2.1460748e-6
+		q33    = 2.2123015e-7
+		root22 = 1.7891679e-6
+		root44 = 7.3636953e-9
+		root54 = 2.1765803e-9
+		rptim  = 4.37526908801129966e-3 // (rad/min)
+		root32 = 3.7393792e-7
+		root52 = 1.1428639e-7
+		znl    = 1.5835218e-4
+		zns    = 1.19459e-5
+	)
+
+	nm := c.nm
+	em := c.em
+	emsq := c.emsq
+	sinim := c.sinim
+	cosim := c.cosim
+	inclm := s.inclo
+
+	s.irez = 0
+	if nm < 0.0052359877 && nm > 0.0034906585 {
+		s.irez = 1
+	}
+	if nm >= 8.26e-3 && nm <= 9.24e-3 && em >= 0.5 {
+		s.irez = 2
+	}
+
+	// solar terms
+	ses := c.ss1 * zns * c.ss5
+	sis := c.ss2 * zns * (c.sz11 + c.sz13)
+	sls := -zns * c.ss3 * (c.sz1 + c.sz3 - 14 - 6*emsq)
+	sghs := c.ss4 * zns * (c.sz31 + c.sz33 - 6)
+	shs := -zns * c.ss2 * (c.sz21 + c.sz23)
+	if inclm < 5.2359877e-2 || inclm > math.Pi-5.2359877e-2 {
+		shs = 0
+	}
+	if sinim != 0 {
+		shs /= sinim
+	}
+	sgs := sghs - cosim*shs
+
+	// lunar terms
+	s.dedt = ses + c.s1*znl*c.s5
+	s.didt = sis + c.s2*znl*(c.z11+c.z13)
+	s.dmdt = sls - znl*c.s3*(c.z1+c.z3-14-6*emsq)
+	sghl := c.s4 * znl * (c.z31 + c.z33 - 6)
+	shll := -znl * c.s2 * (c.z21 + c.z23)
+	if inclm < 5.2359877e-2 || inclm > math.Pi-5.2359877e-2 {
+		shll = 0
+	}
+	s.domdt = sgs + sghl
+	s.dnodt = shs
+	if sinim != 0 {
+		s.domdt -= cosim / sinim * shll
+		s.dnodt += shll / sinim
+	}
+
+	if s.irez == 0 {
+		return
+	}
+
+	// resonance terms
+	theta := math.Mod(s.gsto, twoPi)
+	aonv := math.Pow(nm/xke, x2o3)
+
+	// geopotential resonance for 12 hour orbits
+	if s.irez == 2 {
+		cosisq := cosim * cosim
+		em = s.ecco
+		emsq = eccsq
+		eoc := em * emsq
+		g201 := -0.306 - (em-0.64)*0.440
+
+		var g211, g310, g322, g410, g422, g520, g521, g532, g533 float64
+		if em <= 0.65 {
+			g211 = 3.616 - 13.2470*em + 16.2900*emsq
+			g310 = -19.302 + 117.3900*em - 228.4190*emsq + 156.5910*eoc
+			g322 = -18.9068 + 109.7927*em - 214.6334*emsq + 146.5816*eoc
+			g410 = -41.122 + 242.6940*em - 471.0940*emsq + 313.9530*eoc
+			g422 = -146.407 + 841.8800*em - 1629.014*emsq + 1083.4350*eoc
+			g520 = -532.114 + 3017.977*em - 5740.032*emsq + 3708.2760*eoc
+		} else {
+			g211 = -72.099 + 331.819*em - 508.738*emsq + 266.724*eoc
+			g310 = -346.844 + 1582.851*em - 2415.925*emsq + 1246.113*eoc
+			g322 = -342.585 + 1554.908*em - 2366.899*emsq + 1215.972*eoc
+			g410 = -1052.797 + 4758.686*em - 7193.992*emsq + 3651.957*eoc
+			g422 = -3581.690 + 16178.110*em - 24462.770*emsq + 12422.520*eoc
+			if em > 0.715 {
+				g520 = -5149.66 + 29936.92*em - 54087.36*emsq + 31324.56*eoc
+			} else {
+				g520 = 1464.74 - 4664.75*em + 3763.64*emsq
+			}
+		}
+		if em < 0.7 {
+			g533 = -919.22770 + 4988.6100*em - 9064.7700*emsq + 5542.21*eoc
+			g521 = -822.71072 + 4568.6173*em - 8491.4146*emsq + 5337.524*eoc
+			g532 = -853.66600 + 4690.2500*em - 8624.7700*emsq + 5341.4*eoc
+		} else {
+			g533 = -37995.780 + 161616.52*em - 229838.20*emsq + 109377.94*eoc
+			g521 = -51752.104 + 218913.95*em - 309468.16*emsq + 146349.42*eoc
+			g532 = -40023.880 + 170470.89*em - 242699.48*emsq + 115605.82*eoc
+		}
+
+		sini2 := sinim * sinim
+		f220 := 0.75 * (1 + 2*cosim + cosisq)
+		f221 := 1.5 * sini2
+		f321 := 1.875 * sinim * (1 - 2*cosim - 3*cosisq)
+		f322 := -1.875 * sinim * (1 + 2*cosim - 3*cosisq)
+		f441 := 35 * sini2 * f220
+		f442 := 39.3750 * sini2 * sini2
+		f522 := 9.84375 * sinim * (sini2*(1-2*cosim-5*cosisq) +
+			0.33333333*(-2+4*cosim+6*cosisq))
+		f523 := sinim * (4.92187512*sini2*(-2-4*cosim+10*cosisq) +
+			6.56250012*(1+2*cosim-3*cosisq))
+		f542 := 29.53125 * sinim * (2 - 8*cosim + cosisq*(-12+8*cosim+10*cosisq))
+		f543 := 29.53125 * sinim * (-2 - 8*cosim + cosisq*(12+8*cosim-10*cosisq))
+		xno2 := nm * nm
+		ainv2 := aonv * aonv
+		temp1 := 3 * xno2 * ainv2
+		temp := temp1 * root22
+		s.d2201 = temp * f220 * g201
+		s.d2211 = temp * f221 * g211
+		temp1 *= aonv
+		temp = temp1 * root32
+		s.d3210 = temp * f321 * g310
+		s.d3222 = temp * f322 * g322
+		temp1 *= aonv
+		temp = 2 * temp1 * root44
+		s.d4410 = temp * f441 * g410
+		s.d4422 = temp * f442 * g422
+		temp1 *= aonv
+		temp = temp1 * root52
+		s.d5220 = temp * f522 * g520
+		s.d5232 = temp * f523 * g532
+		temp = 2 * temp1 * root54
+		s.d5421 = temp * f542 * g521
+		s.d5433 = temp * f543 * g533
+		s.xlamo = math.Mod(s.mo+s.nodeo+s.nodeo-theta-theta, twoPi)
+		s.xfact = s.mdot + s.dmdt + 2*(s.nodedot+s.dnodt-rptim) - s.no
+	}
+
+	// synchronous resonance terms
+	if s.irez == 1 {
+		g200 := 1 + emsq*(-2.5+0.8125*emsq)
+		g310 := 1 + 2*emsq
+		g300 := 1 + emsq*(-6+6.60937*emsq)
+		f220 := 0.75 * (1 + cosim) * (1 + cosim)
+		f311 := 0.9375*sinim*sinim*(1+3*cosim) - 0.75*(1+cosim)
+		f330 := 1 + cosim
+		f330 = 1.875 * f330 * f330 * f330
+		s.del1 = 3 * nm * nm * aonv * aonv
+		s.del2 = 2 * s.del1 * f220 * g200 * q22
+		s.del3 = 3 * s.del1 * f330 * g300 * q33 * aonv
+		s.del1 = s.del1 * f311 * g310 * q31 * aonv
+		s.xlamo = math.Mod(s.mo+s.nodeo+s.argpo-theta, twoPi)
+		s.xfact = s.mdot + xpidot - rptim + s.dmdt + s.domdt + s.dnodt - s.no
+	}
+}
+
+// dspace deep space secular effects and numerical integration of the
+// resonance terms. The integrator always restarts from epoch so that the
+// satellite does not need to carry mutable state.
+func (s Satellite) dspace(t, em, argpm, inclm, mm, nodem float64) (float64, float64, float64, float64, float64, float64) {
+	const (
+		fasx2 = 0.13130908
+		fasx4 = 2.8843198
+		fasx6 = 0.37448087
+		g22   = 5.7686396
+		g32   = 0.95240898
+		g44   = 1.8014998
+		g52   = 1.0508330
+		g54   = 4.4108898
+		rptim = 4.37526908801129966e-3 // (rad/min)
+		stepp = 720.0
+		stepn = -720.0
+		step2 = 259200.0
+	)
+
+	nm := s.no
+	theta := math.Mod(s.gsto+t*rptim, twoPi)
+	em += s.dedt * t
+	inclm += s.didt * t
+	argpm += s.domdt * t
+	nodem += s.dnodt * t
+	mm += s.dmdt * t
+
+	if s.irez == 0 {
+		return em, argpm, inclm, mm, nodem, nm
+	}
+
+	atime := float64(0)
+	xni := s.no
+	xli := s.xlamo
+	delt := stepn
+	if t > 0 {
+		delt = stepp
+	}
+
+	var xndt, xnddt, xldot, ft float64
+	for {
+		if s.irez != 2 {
+			xndt = s.del1*math.Sin(xli-fasx2) + s.del2*math.Sin(2*(xli-fasx4)) +
+				s.del3*math.Sin(3*(xli-fasx6))
+			xldot = xni + s.xfact
+			xnddt = s.del1*math.Cos(xli-fasx2) + 2*s.del2*math.Cos(2*(xli-fasx4)) +
+				3*s.del3*math.Cos(3*(xli-fasx6))
+			xnddt *= xldot
+		} else {
+			xomi := s.argpo + s.argpdot*atime
+			x2omi := xomi + xomi
+			x2li := xli + xli
+			xndt = s.d2201*math.Sin(x2omi+xli-g22) + s.d2211*math.Sin(xli-g22) +
+				s.d3210*math.Sin(xomi+xli-g32) + s.d3222*math.Sin(-xomi+xli-g32) +
+				s.d4410*math.Sin(x2omi+x2li-g44) + s.d4422*math.Sin(x2li-g44) +
+				s.d5220*math.Sin(xomi+xli-g52) + s.d5232*math.Sin(-xomi+xli-g52) +
+				s.d5421*math.Sin(xomi+x2li-g54) + s.d5433*math.Sin(-xomi+x2li-g54)
+			xldot = xni + s.xfact
+			xnddt = s.d2201*math.Cos(x2omi+xli-g22) + s.d2211*math.Cos(xli-g22) +
+				s.d3210*math.Cos(xomi+xli-g32) + s.d3222*math.Cos(-xomi+xli-g32) +
+				s.d5220*math.Cos(xomi+xli-g52) + s.d5232*math.Cos(-xomi+xli-g52) +
+				2*(s.d4410*math.Cos(x2omi+x2li-g44)+s.d4422*math.Cos(x2li-g44)+
+					s.d5421*math.Cos(xomi+x2li-g54)+s.d5433*math.Cos(-xomi+x2li-g54))
+			xnddt *= xldot
+		}
+
+		if math.Abs(t-atime) < stepp {
+			ft = t - atime
+			break
+		}
+		xli += xldot*delt + xndt*step2
+		xni += xndt*delt + xnddt*step2
+		atime += delt
+	}
+
+	nm = xni + xndt*ft + xnddt*ft*ft*0.5
+	xl := xli + xldot*ft + xndt*ft*ft*0.5
+	if s.irez != 1 {
+		mm = xl - 2*nodem + 2*theta
+	} else {
+		mm = xl - nodem - argpm + theta
+	}
+
+	return em, argpm, inclm, mm, nodem, nm
+}
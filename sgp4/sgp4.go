@@ -0,0 +1,432 @@
+package sgp4
+
+import (
+	"errors"
+	"math"
+
+	"github.com/wafer-bw/gorbit/epoch"
+	"github.com/wafer-bw/gorbit/tle"
+	"golang.org/x/image/math/f64"
+)
+
+var (
+	ErrEccentricity = errors.New("sgp4: mean eccentricity out of range")
+	ErrMeanMotion   = errors.New("sgp4: mean motion less than zero")
+	ErrPerturbed    = errors.New("sgp4: perturbed eccentricity out of range")
+	ErrSemiLatus    = errors.New("sgp4: semi-latus rectum less than zero")
+	ErrDecayed      = errors.New("sgp4: satellite has decayed")
+	ErrInvalidOrbit = errors.New("sgp4: invalid orbit")
+)
+
+// WGS-72 constants which the element sets are fitted with.
+const (
+	mu      float64 = 398600.8 // (km^3/s^2)
+	re      float64 = 6378.135 // (km)
+	j2      float64 = 0.001082616
+	j3      float64 = -0.00000253881
+	j4      float64 = -0.00000165597
+	j3oj2   float64 = j3 / j2
+	twoPi   float64 = 2 * math.Pi
+	x2o3    float64 = 2.0 / 3.0
+	temp4   float64 = 1.5e-12
+	minDay  float64 = 1440
+	epoch50 float64 = 2433281.5 // Julian Date of 1949 December 31 00:00 UT
+)
+
+// xke is sqrt(mu) in earth radii^1.5 per minute.
+var xke = 60 / math.Sqrt(re*re*re/mu)
+
+// Satellite initialised from a two-line element set which can be
+// propagated with SGP4 (near earth) or SDP4 (deep space, period >= 225
+// minutes) depending on its orbit.
+//
+// The zero value is not usable, use New. Satellites are immutable once
+// created so they are safe for concurrent use.
+//
+// https://celestrak.org/publications/AIAA/2006-6753/
+type Satellite struct {
+	epoch float64 // UTC Julian Date of the element set
+
+	// mean elements (rad, rad/min)
+	bstar, ecco, argpo, inclo, mo, no, nodeo float64
+
+	deep, isimp bool
+
+	aycof, con41, cc1, cc4, cc5, d2, d3, d4, delmo, eta, argpdot, omgcof float64
+	sinmao, t2cof, t3cof, t4cof, t5cof, x1mth2, x7thm1, mdot, nodedot    float64
+	xlcof, xmcof, nodecf                                                 float64
+
+	// deep space
+	irez                                                                  int
+	d2201, d2211, d3210, d3222, d4410, d4422, d5220, d5232, d5421, d5433  float64
+	dedt, del1, del2, del3, didt, dmdt, dnodt, domdt                      float64
+	e3, ee2, peo, pgho, pho, pinco, plo, se2, se3, sgh2, sgh3, sgh4, sh2  float64
+	sh3, si2, si3, sl2, sl3, sl4, gsto, xfact, xgh2, xgh3, xgh4, xh2, xh3 float64
+	xi2, xi3, xl2, xl3, xl4, xlamo, zmol, zmos                            float64
+}
+
+// New Satellite from a two-line element set.
+//
+// An error is returned if the elements describe an orbit SGP4 cannot
+// propagate.
+func New(t tle.TLE) (Satellite, error) {
+	xpdotp := minDay / twoPi // (rev/day) / (rad/min)
+
+	s := Satellite{
+		epoch: t.Epoch(),
+		bstar: t.BStar,
+		ecco:  t.Eccentricity,
+		argpo: t.ArgPerigee * math.Pi / 180,
+		inclo: t.Inclination * math.Pi / 180,
+		mo:    t.MeanAnomaly * math.Pi / 180,
+		no:    t.MeanMotion / xpdotp,
+		nodeo: t.RAAN * math.Pi / 180,
+	}
+	if err := s.init(); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// Epoch of the satellite's element set as a UTC Julian Date (days).
+func (s Satellite) Epoch() float64 {
+	return s.epoch
+}
+
+// StateVectors at t seconds after the element set epoch.
+//
+// accepts:
+// t: time since epoch (seconds).
+//
+// returns:
+// r: position in the TEME frame (m),
+// v: velocity in the TEME frame (m/s).
+//
+// TEME is the true equator, mean equinox frame of date used by SGP4.
+//
+// https://celestrak.org/publications/AIAA/2006-6753/
+func (s Satellite) StateVectors(t float64) (f64.Vec3, f64.Vec3, error) {
+	r, v, err := s.propagate(t / 60)
+	for i := range r {
+		r[i] *= 1000
+		v[i] *= 1000
+	}
+	return r, v, err
+}
+
+// StateVectorsAt a UTC Julian Date.
+//
+// accepts:
+// jd: UTC Julian Date (days).
+//
+// See StateVectors for more details.
+func (s Satellite) StateVectorsAt(jd float64) (f64.Vec3, f64.Vec3, error) {
+	return s.StateVectors(epoch.Seconds(jd, s.epoch))
+}
+
+// init is sgp4init from Vallado's reference implementation.
+func (s *Satellite) init() error {
+	ss := 78/re + 1
+	qzms2t := math.Pow((120-78)/re, 4)
+
+	// initl
+	eccsq := s.ecco * s.ecco
+	omeosq := 1 - eccsq
+	rteosq := math.Sqrt(omeosq)
+	cosio := math.Cos(s.inclo)
+	cosio2 := cosio * cosio
+	ak := math.Pow(xke/s.no, x2o3)
+	d1 := 0.75 * j2 * (3*cosio2 - 1) / (rteosq * omeosq)
+	del := d1 / (ak * ak)
+	adel := ak * (1 - del*del - del*(1.0/3.0+134*del*del/81))
+	del = d1 / (adel * adel)
+	s.no /= 1 + del
+	ao := math.Pow(xke/s.no, x2o3)
+	sinio := math.Sin(s.inclo)
+	po := ao * omeosq
+	con42 := 1 - 5*cosio2
+	s.con41 = -con42 - cosio2 - cosio2
+	posq := po * po
+	rp := ao * (1 - s.ecco)
+	s.gsto = gstime(s.epoch)
+
+	if omeosq < 0 && s.no < 0 {
+		return ErrInvalidOrbit
+	}
+
+	s.isimp = rp < 220/re+1
+	sfour := ss
+	qzms24 := qzms2t
+	perige := (rp - 1) * re
+	if perige < 156 {
+		sfour = perige - 78
+		if perige < 98 {
+			sfour = 20
+		}
+		qzms24 = math.Pow((120-sfour)/re, 4)
+		sfour = sfour/re + 1
+	}
+	pinvsq := 1 / posq
+
+	tsi := 1 / (ao - sfour)
+	s.eta = ao * s.ecco * tsi
+	etasq := s.eta * s.eta
+	eeta := s.ecco * s.eta
+	psisq := math.Abs(1 - etasq)
+	coef := qzms24 * math.Pow(tsi, 4)
+	coef1 := coef / math.Pow(psisq, 3.5)
+	cc2 := coef1 * s.no * (ao*(1+1.5*etasq+eeta*(4+etasq)) +
+		0.375*j2*tsi/psisq*s.con41*(8+3*etasq*(8+etasq)))
+	s.cc1 = s.bstar * cc2
+	cc3 := float64(0)
+	if s.ecco > 1.0e-4 {
+		cc3 = -2 * coef * tsi * j3oj2 * s.no * sinio / s.ecco
+	}
+	s.x1mth2 = 1 - cosio2
+	s.cc4 = 2 * s.no * coef1 * ao * omeosq * (s.eta*(2+0.5*etasq) + s.ecco*(0.5+2*etasq) -
+		j2*tsi/(ao*psisq)*(-3*s.con41*(1-2*eeta+etasq*(1.5-0.5*eeta))+
+			0.75*s.x1mth2*(2*etasq-eeta*(1+etasq))*math.Cos(2*s.argpo)))
+	s.cc5 = 2 * coef1 * ao * omeosq * (1 + 2.75*(etasq+eeta) + eeta*etasq)
+	cosio4 := cosio2 * cosio2
+	temp1 := 1.5 * j2 * pinvsq * s.no
+	temp2 := 0.5 * temp1 * j2 * pinvsq
+	temp3 := -0.46875 * j4 * pinvsq * pinvsq * s.no
+	s.mdot = s.no + 0.5*temp1*rteosq*s.con41 + 0.0625*temp2*rteosq*(13-78*cosio2+137*cosio4)
+	s.argpdot = -0.5*temp1*con42 + 0.0625*temp2*(7-114*cosio2+395*cosio4) +
+		temp3*(3-36*cosio2+49*cosio4)
+	xhdot1 := -temp1 * cosio
+	s.nodedot = xhdot1 + (0.5*temp2*(4-19*cosio2)+2*temp3*(3-7*cosio2))*cosio
+	xpidot := s.argpdot + s.nodedot
+	s.omgcof = s.bstar * cc3 * math.Cos(s.argpo)
+	s.xmcof = 0
+	if s.ecco > 1.0e-4 {
+		s.xmcof = -x2o3 * coef * s.bstar / eeta
+	}
+	s.nodecf = 3.5 * omeosq * xhdot1 * s.cc1
+	s.t2cof = 1.5 * s.cc1
+	if math.Abs(cosio+1) > temp4 {
+		s.xlcof = -0.25 * j3oj2 * sinio * (3 + 5*cosio) / (1 + cosio)
+	} else {
+		s.xlcof = -0.25 * j3oj2 * sinio * (3 + 5*cosio) / temp4
+	}
+	s.aycof = -0.5 * j3oj2 * sinio
+	s.delmo = math.Pow(1+s.eta*math.Cos(s.mo), 3)
+	s.sinmao = math.Sin(s.mo)
+	s.x7thm1 = 7*cosio2 - 1
+
+	if twoPi/s.no >= 225 {
+		s.deep = true
+		s.isimp = true
+		c := s.dscom(0, s.ecco, s.argpo, s.inclo, s.nodeo, s.no)
+		s.dsinit(c, eccsq, xpidot)
+	}
+
+	if !s.isimp {
+		cc1sq := s.cc1 * s.cc1
+		s.d2 = 4 * ao * tsi * cc1sq
+		temp := s.d2 * tsi * s.cc1 / 3
+		s.d3 = (17*ao + sfour) * temp
+		s.d4 = 0.5 * temp * ao * tsi * (221*ao + 31*sfour) * s.cc1
+		s.t3cof = s.d2 + 2*cc1sq
+		s.t4cof = 0.25 * (3*s.d3 + s.cc1*(12*s.d2+10*cc1sq))
+		s.t5cof = 0.2 * (3*s.d4 + 12*s.cc1*s.d3 + 6*s.d2*s.d2 + 15*cc1sq*(2*s.d2+cc1sq))
+	}
+
+	_, _, err := s.propagate(0)
+	if errors.Is(err, ErrDecayed) {
+		return nil
+	}
+	return err
+}
+
+// propagate is sgp4 from Vallado's reference implementation with t in
+// minutes since epoch and results in km and km/s.
+func (s Satellite) propagate(t float64) (f64.Vec3, f64.Vec3, error) {
+	vkmpersec := re * xke / 60
+
+	// secular gravity and atmospheric drag
+	xmdf := s.mo + s.mdot*t
+	argpdf := s.argpo + s.argpdot*t
+	nodedf := s.nodeo + s.nodedot*t
+	argpm := argpdf
+	mm := xmdf
+	t2 := t * t
+	nodem := nodedf + s.nodecf*t2
+	tempa := 1 - s.cc1*t
+	tempe := s.bstar * s.cc4 * t
+	templ := s.t2cof * t2
+
+	if !s.isimp {
+		delomg := s.omgcof * t
+		delm := s.xmcof * (math.Pow(1+s.eta*math.Cos(xmdf), 3) - s.delmo)
+		temp := delomg + delm
+		mm = xmdf + temp
+		argpm = argpdf - temp
+		t3 := t2 * t
+		t4 := t3 * t
+		tempa = tempa - s.d2*t2 - s.d3*t3 - s.d4*t4
+		tempe += s.bstar * s.cc5 * (math.Sin(mm) - s.sinmao)
+		templ = templ + s.t3cof*t3 + t4*(s.t4cof+t*s.t5cof)
+	}
+
+	nm := s.no
+	em := s.ecco
+	inclm := s.inclo
+	if s.deep {
+		em, argpm, inclm, mm, nodem, nm = s.dspace(t, em, argpm, inclm, mm, nodem)
+	}
+
+	if nm <= 0 {
+		return f64.Vec3{}, f64.Vec3{}, ErrMeanMotion
+	}
+	am := math.Pow(xke/nm, x2o3) * tempa * tempa
+	nm = xke / math.Pow(am, 1.5)
+	em -= tempe
+
+	if em >= 1 || em < -0.001 {
+		return f64.Vec3{}, f64.Vec3{}, ErrEccentricity
+	}
+	if em < 1.0e-6 {
+		em = 1.0e-6
+	}
+	mm += s.no * templ
+	xlm := mm + argpm + nodem
+	nodem = math.Mod(nodem, twoPi)
+	argpm = math.Mod(argpm, twoPi)
+	xlm = math.Mod(xlm, twoPi)
+	mm = math.Mod(xlm-argpm-nodem, twoPi)
+
+	// lunar-solar periodics
+	ep := em
+	xincp := inclm
+	argpp := argpm
+	nodep := nodem
+	mp := mm
+	sinip := math.Sin(inclm)
+	cosip := math.Cos(inclm)
+	aycof := s.aycof
+	xlcof := s.xlcof
+	con41 := s.con41
+	x1mth2 := s.x1mth2
+	x7thm1 := s.x7thm1
+	if s.deep {
+		ep, xincp, nodep, argpp, mp = s.dpper(t, false, ep, xincp, nodep, argpp, mp)
+		if xincp < 0 {
+			xincp = -xincp
+			nodep += math.Pi
+			argpp -= math.Pi
+		}
+		if ep < 0 || ep > 1 {
+			return f64.Vec3{}, f64.Vec3{}, ErrPerturbed
+		}
+
+		sinip = math.Sin(xincp)
+		cosip = math.Cos(xincp)
+		aycof = -0.5 * j3oj2 * sinip
+		if math.Abs(cosip+1) > temp4 {
+			xlcof = -0.25 * j3oj2 * sinip * (3 + 5*cosip) / (1 + cosip)
+		} else {
+			xlcof = -0.25 * j3oj2 * sinip * (3 + 5*cosip) / temp4
+		}
+	}
+
+	// long period periodics
+	axnl := ep * math.Cos(argpp)
+	temp := 1 / (am * (1 - ep*ep))
+	aynl := ep*math.Sin(argpp) + temp*aycof
+	xl := mp + argpp + nodep + temp*xlcof*axnl
+
+	// solve kepler's equation
+	u := math.Mod(xl-nodep, twoPi)
+	eo1 := u
+	tem5 := 9999.9
+	var sineo1, coseo1 float64
+	for ktr := 1; math.Abs(tem5) >= 1.0e-12 && ktr <= 10; ktr++ {
+		sineo1 = math.Sin(eo1)
+		coseo1 = math.Cos(eo1)
+		tem5 = 1 - coseo1*axnl - sineo1*aynl
+		tem5 = (u - aynl*coseo1 + axnl*sineo1 - eo1) / tem5
+		if math.Abs(tem5) >= 0.95 {
+			tem5 = math.Copysign(0.95, tem5)
+		}
+		eo1 += tem5
+	}
+
+	// short period preliminary quantities
+	ecose := axnl*coseo1 + aynl*sineo1
+	esine := axnl*sineo1 - aynl*coseo1
+	el2 := axnl*axnl + aynl*aynl
+	pl := am * (1 - el2)
+	if pl < 0 {
+		return f64.Vec3{}, f64.Vec3{}, ErrSemiLatus
+	}
+	rl := am * (1 - ecose)
+	rdotl := math.Sqrt(am) * esine / rl
+	rvdotl := math.Sqrt(pl) / rl
+	betal := math.Sqrt(1 - el2)
+	temp = esine / (1 + betal)
+	sinu := am / rl * (sineo1 - aynl - axnl*temp)
+	cosu := am / rl * (coseo1 - axnl + aynl*temp)
+	su := math.Atan2(sinu, cosu)
+	sin2u := (cosu + cosu) * sinu
+	cos2u := 1 - 2*sinu*sinu
+	temp = 1 / pl
+	temp1 := 0.5 * j2 * temp
+	temp2 := temp1 * temp
+
+	if s.deep {
+		cosisq := cosip * cosip
+		con41 = 3*cosisq - 1
+		x1mth2 = 1 - cosisq
+		x7thm1 = 7*cosisq - 1
+	}
+
+	// short period periodics
+	mrt := rl*(1-1.5*temp2*betal*con41) + 0.5*temp1*x1mth2*cos2u
+	su -= 0.25 * temp2 * x7thm1 * sin2u
+	xnode := nodep + 1.5*temp2*cosip*sin2u
+	xinc := xincp + 1.5*temp2*cosip*sinip*cos2u
+	mvt := rdotl - nm*temp1*x1mth2*sin2u/xke
+	rvdot := rvdotl + nm*temp1*(x1mth2*cos2u+1.5*con41)/xke
+
+	// orientation vectors
+	sinsu, cossu := math.Sincos(su)
+	snod, cnod := math.Sincos(xnode)
+	sini, cosi := math.Sincos(xinc)
+	xmx := -snod * cosi
+	xmy := cnod * cosi
+	ux := xmx*sinsu + cnod*cossu
+	uy := xmy*sinsu + snod*cossu
+	uz := sini * sinsu
+	vx := xmx*cossu - cnod*sinsu
+	vy := xmy*cossu - snod*sinsu
+	vz := sini * cossu
+
+	r := f64.Vec3{
+		mrt * ux * re,
+		mrt * uy * re,
+		mrt * uz * re,
+	}
+	v := f64.Vec3{
+		(mvt*ux + rvdot*vx) * vkmpersec,
+		(mvt*uy + rvdot*vy) * vkmpersec,
+		(mvt*uz + rvdot*vz) * vkmpersec,
+	}
+
+	if mrt < 1 {
+		return r, v, ErrDecayed
+	}
+	return r, v, nil
+}
+
+// gstime Greenwich sidereal time (rad) at a UT1 Julian Date.
+func gstime(jd float64) float64 {
+	tut1 := (jd - epoch.J2000) / epoch.DaysPerCentury
+	temp := -6.2e-6*tut1*tut1*tut1 + 0.093104*tut1*tut1 +
+		(876600*3600+8640184.812866)*tut1 + 67310.54841 // (s)
+	temp = math.Mod(temp*math.Pi/180/240, twoPi)
+	if temp < 0 {
+		temp += twoPi
+	}
+	return temp
+}
@@ -0,0 +1,164 @@
+package sgp4_test
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/sgp4"
+	"github.com/wafer-bw/gorbit/tle"
+	"github.com/wafer-bw/gorbit/vec3"
+)
+
+type vector struct {
+	tsince float64
+	rv     [6]float64
+}
+
+type fixture struct {
+	tle     tle.TLE
+	vectors []vector
+}
+
+func readFixtures(t *testing.T, path string) []fixture {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	fixtures := []fixture{}
+	var line1 string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "1 "):
+			line1 = line
+		case strings.HasPrefix(line, "2 "):
+			e, err := tle.Parse(line1, line)
+			require.NoError(t, err)
+			fixtures = append(fixtures, fixture{tle: e})
+		default:
+			fields := strings.Fields(line)
+			require.Len(t, fields, 7)
+			vec := vector{}
+			vec.tsince, err = strconv.ParseFloat(fields[0], 64)
+			require.NoError(t, err)
+			for i := range vec.rv {
+				vec.rv[i], err = strconv.ParseFloat(fields[i+1], 64)
+				require.NoError(t, err)
+			}
+			last := &fixtures[len(fixtures)-1]
+			last.vectors = append(last.vectors, vec)
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return fixtures
+}
+
+func TestStateVectors(t *testing.T) {
+	for _, fx := range readFixtures(t, "testdata/vallado.txt") {
+		fx := fx
+		t.Run(fmt.Sprintf("succeed in matching verification vectors for %05d", fx.tle.CatalogNumber), func(t *testing.T) {
+			s, err := sgp4.New(fx.tle)
+			require.NoError(t, err)
+			for _, vec := range fx.vectors {
+				r, v, err := s.StateVectors(vec.tsince * 60)
+				require.NoError(t, err)
+				for i := 0; i < 3; i++ {
+					require.Equal(t, fmt.Sprintf("%.8f", vec.rv[i]), fmt.Sprintf("%.8f", r[i]/1000), "r[%d] at %.0f min", i, vec.tsince)
+					require.Equal(t, fmt.Sprintf("%.9f", vec.rv[i+3]), fmt.Sprintf("%.9f", v[i]/1000), "v[%d] at %.0f min", i, vec.tsince)
+				}
+			}
+		})
+	}
+}
+
+func TestDeepSpace(t *testing.T) {
+	// Only the t=0 rows of the deep space element sets are in vallado.txt
+	// so the rest of the SDP4 paths are bounded by the apsides of the mean
+	// elements rather than compared with published vectors.
+	for _, lines := range [][2]string{
+		{ // 12 hour resonance
+			"1 08195U 75081A   06176.33215444  .00000099  00000-0  11873-3 0   813",
+			"2 08195  64.1586 279.0717 6877146 264.7651  20.2257  2.00491383225656",
+		},
+		{ // 12 hour resonance
+			"1 09880U 77021A   06176.56157475  .00000421  00000-0  10000-3 0  9814",
+			"2 09880  64.5968 349.3786 7069051 270.0229  16.3320  2.00813614112380",
+		},
+		{ // 24 hour resonance
+			"1 28626U 05008A   06176.46683397 -.00000205  00000-0  10000-3 0  2190",
+			"2 28626   0.0019 286.9433 0000335  13.7918  55.6504  1.00270176  4891",
+		},
+		{ // no resonance
+			"1 11801U          80230.29629788  .01431103  00000-0  14311-1      13",
+			"2 11801  46.7916 230.4354 7318036  47.4722  10.4117  2.28537848    13",
+		},
+	} {
+		e, err := tle.Parse(lines[0], lines[1])
+		require.NoError(t, err)
+		t.Run(fmt.Sprintf("succeed in staying between the apsides of %05d", e.CatalogNumber), func(t *testing.T) {
+			s, err := sgp4.New(e)
+			require.NoError(t, err)
+			n := e.MeanMotion * 2 * math.Pi / 86400
+			a := math.Cbrt(398600.8e9 / (n * n))
+			for tsince := -1440.0; tsince <= 2880; tsince += 30 {
+				r, _, err := s.StateVectors(tsince * 60)
+				require.NoError(t, err)
+				require.Greater(t, vec3.Magnitude(r), 0.95*a*(1-e.Eccentricity), "at %.0f min", tsince)
+				require.Less(t, vec3.Magnitude(r), 1.05*a*(1+e.Eccentricity), "at %.0f min", tsince)
+			}
+		})
+	}
+
+	e, err := tle.Parse(
+		"1 08195U 75081A   06176.33215444  .00000099  00000-0  11873-3 0   813",
+		"2 08195  64.1586 279.0717 6877146 264.7651  20.2257  2.00491383225656",
+	)
+	require.NoError(t, err)
+	s, err := sgp4.New(e)
+	require.NoError(t, err)
+
+	t.Run("succeed in propagating a resonant orbit backwards", func(t *testing.T) {
+		// the resonance integrator restarts from epoch so stepping back to
+		// -t and forward again must not depend on the order of calls.
+		r1, v1, err := s.StateVectors(-2880 * 60)
+		require.NoError(t, err)
+		_, _, err = s.StateVectors(2880 * 60)
+		require.NoError(t, err)
+		r2, v2, err := s.StateVectors(-2880 * 60)
+		require.NoError(t, err)
+		require.Equal(t, r1, r2)
+		require.Equal(t, v1, v2)
+	})
+	t.Run("succeed in propagating to a julian date", func(t *testing.T) {
+		r1, v1, err := s.StateVectors(3600)
+		require.NoError(t, err)
+		r2, v2, err := s.StateVectorsAt(s.Epoch() + 3600.0/86400)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("%.0f", r1), fmt.Sprintf("%.0f", r2))
+		require.Equal(t, fmt.Sprintf("%.2f", v1), fmt.Sprintf("%.2f", v2))
+	})
+}
+
+func TestErrors(t *testing.T) {
+	t.Run("fail when the satellite has decayed", func(t *testing.T) {
+		e, err := tle.Parse(
+			"1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753",
+			"2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667",
+		)
+		require.NoError(t, err)
+		e.BStar = 1
+		s, err := sgp4.New(e)
+		require.NoError(t, err)
+		_, _, err = s.StateVectors(100 * 86400)
+		require.ErrorIs(t, err, sgp4.ErrDecayed)
+	})
+}
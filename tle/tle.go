@@ -0,0 +1,251 @@
+package tle
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wafer-bw/gorbit/epoch"
+)
+
+var (
+	ErrLineLength = errors.New("tle: line too short")
+	ErrLineNumber = errors.New("tle: unexpected line number")
+	ErrChecksum   = errors.New("tle: checksum mismatch")
+	ErrCatalog    = errors.New("tle: catalog numbers do not match")
+	ErrField      = errors.New("tle: malformed field")
+)
+
+// lineLength of a TLE line including the checksum.
+const lineLength = 69
+
+// TLE two-line element set.
+//
+// Name:                    satellite name from the title line, if any,
+// CatalogNumber:           NORAD catalog number,
+// Classification:          U, C or S,
+// InternationalDesignator: launch year, number and piece,
+// EpochYear:               four digit epoch year,
+// EpochDay:                day of the year and fractional portion of the day,
+// MeanMotionDot:           first derivative of mean motion / 2  (rev/day^2),
+// MeanMotionDDot:          second derivative of mean motion / 6 (rev/day^3),
+// BStar:                   drag term                            (1/earth radii),
+// ElementSetNumber:        element set number,
+// Inclination:             inclination                          (deg),
+// RAAN:                    right ascension of the ascending node (deg),
+// Eccentricity:            eccentricity                         (0-1),
+// ArgPerigee:              argument of perigee                  (deg),
+// MeanAnomaly:             mean anomaly                         (deg),
+// MeanMotion:              mean motion                          (rev/day),
+// RevolutionNumber:        revolution number at epoch.
+//
+// https://celestrak.org/NORAD/documentation/tle-fmt.php
+type TLE struct {
+	Name                    string
+	CatalogNumber           int
+	Classification          byte
+	InternationalDesignator string
+	EpochYear               int
+	EpochDay                float64
+	MeanMotionDot           float64
+	MeanMotionDDot          float64
+	BStar                   float64
+	ElementSetNumber        int
+	Inclination             float64
+	RAAN                    float64
+	Eccentricity            float64
+	ArgPerigee              float64
+	MeanAnomaly             float64
+	MeanMotion              float64
+	RevolutionNumber        int
+}
+
+// Epoch of the element set as a UTC Julian Date (days).
+func (t TLE) Epoch() float64 {
+	return epoch.JulianDate(jan0(t.EpochYear)) + t.EpochDay
+}
+
+// Parse a two-line element set.
+//
+// line1: first line of the element set,
+// line2: second line of the element set.
+//
+// Both lines must have valid checksums. Characters after column 69 are
+// ignored.
+func Parse(line1, line2 string) (TLE, error) {
+	t := TLE{}
+	if err := check(line1, '1'); err != nil {
+		return t, err
+	}
+	if err := check(line2, '2'); err != nil {
+		return t, err
+	}
+
+	p := parser{}
+	t.CatalogNumber = p.int(line1, 3, 7)
+	t.Classification = line1[7]
+	t.InternationalDesignator = strings.TrimSpace(line1[9:17])
+	t.EpochYear = p.int(line1, 19, 20)
+	if t.EpochYear < 57 {
+		t.EpochYear += 2000
+	} else {
+		t.EpochYear += 1900
+	}
+	t.EpochDay = p.float(line1, 21, 32)
+	t.MeanMotionDot = p.float(line1, 34, 43)
+	t.MeanMotionDDot = p.exp(line1, 45, 52)
+	t.BStar = p.exp(line1, 54, 61)
+	t.ElementSetNumber = p.int(line1, 65, 68)
+
+	if p.int(line2, 3, 7) != t.CatalogNumber && p.err == nil {
+		return t, ErrCatalog
+	}
+	t.Inclination = p.float(line2, 9, 16)
+	t.RAAN = p.float(line2, 18, 25)
+	t.Eccentricity = p.float(line2, 27, 33) * 1e-7
+	t.ArgPerigee = p.float(line2, 35, 42)
+	t.MeanAnomaly = p.float(line2, 44, 51)
+	t.MeanMotion = p.float(line2, 53, 63)
+	t.RevolutionNumber = p.int(line2, 64, 68)
+
+	return t, p.err
+}
+
+// ParseAll element sets from a reader in either two-line or three-line
+// (title line followed by two lines) format. Blank lines are skipped.
+func ParseAll(r io.Reader) ([]TLE, error) {
+	tles := []TLE{}
+	name := ""
+	var line1 string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r\n")
+		switch {
+		case strings.TrimSpace(line) == "":
+			continue
+		case strings.HasPrefix(line, "1 ") && line1 == "":
+			line1 = line
+		case strings.HasPrefix(line, "2 ") && line1 != "":
+			t, err := Parse(line1, line)
+			if err != nil {
+				return tles, err
+			}
+			t.Name = name
+			tles = append(tles, t)
+			name, line1 = "", ""
+		default:
+			if line1 != "" {
+				return tles, fmt.Errorf("%w: expected line 2 after %q", ErrLineNumber, line1)
+			}
+			name = strings.TrimSpace(strings.TrimPrefix(line, "0 "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return tles, err
+	}
+	if line1 != "" {
+		return tles, fmt.Errorf("%w: missing line 2 after %q", ErrLineNumber, line1)
+	}
+	return tles, nil
+}
+
+// Checksum of a TLE line which is the sum of all digits in the first 68
+// columns plus one for each minus sign, modulo 10.
+func Checksum(line string) int {
+	sum := 0
+	for i := 0; i < len(line) && i < lineLength-1; i++ {
+		switch c := line[i]; {
+		case c >= '0' && c <= '9':
+			sum += int(c - '0')
+		case c == '-':
+			sum++
+		}
+	}
+	return sum % 10
+}
+
+// jan0 is the day before January 1st of year which is day 0 of the
+// epoch day count.
+func jan0(year int) time.Time {
+	return time.Date(year, time.January, 0, 0, 0, 0, 0, time.UTC)
+}
+
+func check(line string, number byte) error {
+	if len(line) < lineLength {
+		return fmt.Errorf("%w: %q", ErrLineLength, line)
+	}
+	if line[0] != number {
+		return fmt.Errorf("%w: %q", ErrLineNumber, line)
+	}
+	if int(line[lineLength-1]-'0') != Checksum(line) {
+		return fmt.Errorf("%w: %q", ErrChecksum, line)
+	}
+	return nil
+}
+
+// parser of fixed width fields which records the first error encountered.
+// Column numbers are 1 based and inclusive to match the TLE format docs.
+type parser struct {
+	err error
+}
+
+func (p *parser) field(line string, from, to int) string {
+	return strings.TrimSpace(line[from-1 : to])
+}
+
+func (p *parser) int(line string, from, to int) int {
+	s := p.field(line, from, to)
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("%w: columns %d-%d of %q", ErrField, from, to, line)
+	}
+	return v
+}
+
+func (p *parser) float(line string, from, to int) float64 {
+	s := p.field(line, from, to)
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("%w: columns %d-%d of %q", ErrField, from, to, line)
+	}
+	return v
+}
+
+// exp parses fields with an implied leading decimal point and a trailing
+// power of ten such as " 12345-3" which is 0.12345e-3.
+func (p *parser) exp(line string, from, to int) float64 {
+	s := p.field(line, from, to)
+	if s == "" {
+		return 0
+	}
+	sign := float64(1)
+	if s[0] == '-' || s[0] == '+' {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+	if len(s) < 3 {
+		if p.err == nil {
+			p.err = fmt.Errorf("%w: columns %d-%d of %q", ErrField, from, to, line)
+		}
+		return 0
+	}
+	mantissa, err1 := strconv.ParseFloat("0."+strings.TrimSpace(s[:len(s)-2]), 64)
+	exponent, err2 := strconv.Atoi(s[len(s)-2:])
+	if (err1 != nil || err2 != nil) && p.err == nil {
+		p.err = fmt.Errorf("%w: columns %d-%d of %q", ErrField, from, to, line)
+	}
+	return sign * mantissa * math.Pow(10, float64(exponent))
+}
@@ -0,0 +1,74 @@
+package tle_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wafer-bw/gorbit/tle"
+)
+
+const (
+	line1 = "1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753"
+	line2 = "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("succeed in parsing a two-line element set", func(t *testing.T) {
+		e, err := tle.Parse(line1, line2)
+		require.NoError(t, err)
+		require.Equal(t, 5, e.CatalogNumber)
+		require.Equal(t, byte('U'), e.Classification)
+		require.Equal(t, "58002B", e.InternationalDesignator)
+		require.Equal(t, 2000, e.EpochYear)
+		require.Equal(t, "179.78495062", fmt.Sprintf("%.8f", e.EpochDay))
+		require.Equal(t, "2.3e-07", fmt.Sprintf("%.1e", e.MeanMotionDot))
+		require.Equal(t, "2.8098e-05", fmt.Sprintf("%.4e", e.BStar))
+		require.Equal(t, 475, e.ElementSetNumber)
+		require.Equal(t, "34.2682", fmt.Sprintf("%.4f", e.Inclination))
+		require.Equal(t, "348.7242", fmt.Sprintf("%.4f", e.RAAN))
+		require.Equal(t, "0.1859667", fmt.Sprintf("%.7f", e.Eccentricity))
+		require.Equal(t, "331.7664", fmt.Sprintf("%.4f", e.ArgPerigee))
+		require.Equal(t, "19.3264", fmt.Sprintf("%.4f", e.MeanAnomaly))
+		require.Equal(t, "10.82419157", fmt.Sprintf("%.8f", e.MeanMotion))
+		require.Equal(t, 41366, e.RevolutionNumber)
+	})
+	t.Run("succeed in calculating the epoch julian date", func(t *testing.T) {
+		e, err := tle.Parse(line1, line2)
+		require.NoError(t, err)
+		require.Equal(t, "2451723.28495062", fmt.Sprintf("%.8f", e.Epoch()))
+	})
+	t.Run("fail on a checksum mismatch", func(t *testing.T) {
+		_, err := tle.Parse(line1[:68]+"0", line2)
+		require.ErrorIs(t, err, tle.ErrChecksum)
+	})
+	t.Run("fail on a short line", func(t *testing.T) {
+		_, err := tle.Parse(line1[:60], line2)
+		require.ErrorIs(t, err, tle.ErrLineLength)
+	})
+	t.Run("fail on swapped lines", func(t *testing.T) {
+		_, err := tle.Parse(line2, line1)
+		require.ErrorIs(t, err, tle.ErrLineNumber)
+	})
+}
+
+func TestParseAll(t *testing.T) {
+	t.Run("succeed in parsing two-line and three-line element sets", func(t *testing.T) {
+		input := strings.Join([]string{line1, line2, "", "VANGUARD 1", line1, line2}, "\n")
+		tles, err := tle.ParseAll(strings.NewReader(input))
+		require.NoError(t, err)
+		require.Len(t, tles, 2)
+		require.Equal(t, "", tles[0].Name)
+		require.Equal(t, "VANGUARD 1", tles[1].Name)
+	})
+	t.Run("fail when line 2 is missing", func(t *testing.T) {
+		_, err := tle.ParseAll(strings.NewReader(line1))
+		require.ErrorIs(t, err, tle.ErrLineNumber)
+	})
+}
+
+func TestChecksum(t *testing.T) {
+	require.Equal(t, 3, tle.Checksum(line1))
+	require.Equal(t, 7, tle.Checksum(line2))
+}
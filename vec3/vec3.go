@@ -42,3 +42,32 @@ func Magnitude(v f64.Vec3) float64 {
 func Dot(v1, v2 f64.Vec3) float64 {
 	return v1[0]*v2[0] + v1[1]*v2[1] + v1[2]*v2[2]
 }
+
+func Add(v1, v2 f64.Vec3) f64.Vec3 {
+	v1[0] = v1[0] + v2[0]
+	v1[1] = v1[1] + v2[1]
+	v1[2] = v1[2] + v2[2]
+	return v1
+}
+
+func Normalize(v f64.Vec3) f64.Vec3 {
+	return DivScalar(v, Magnitude(v))
+}
+
+// MulMat multiplies the row-major matrix m by v.
+func MulMat(m f64.Mat3, v f64.Vec3) f64.Vec3 {
+	return f64.Vec3{
+		m[0]*v[0] + m[1]*v[1] + m[2]*v[2],
+		m[3]*v[0] + m[4]*v[1] + m[5]*v[2],
+		m[6]*v[0] + m[7]*v[1] + m[8]*v[2],
+	}
+}
+
+// MulMatT multiplies the transpose of the row-major matrix m by v.
+func MulMatT(m f64.Mat3, v f64.Vec3) f64.Vec3 {
+	return f64.Vec3{
+		m[0]*v[0] + m[3]*v[1] + m[6]*v[2],
+		m[1]*v[0] + m[4]*v[1] + m[7]*v[2],
+		m[2]*v[0] + m[5]*v[1] + m[8]*v[2],
+	}
+}